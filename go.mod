@@ -5,9 +5,7 @@ go 1.17
 require (
 	github.com/google/gopacket v1.1.19
 	golang.org/x/sync v0.5.0
+	golang.org/x/sys v0.0.0-20210917161153-d61c044b1678
 )
 
-require (
-	golang.org/x/net v0.0.0-20210917221730-978cfadd31cf // indirect
-	golang.org/x/sys v0.0.0-20210917161153-d61c044b1678 // indirect
-)
+require golang.org/x/net v0.0.0-20210917221730-978cfadd31cf // indirect
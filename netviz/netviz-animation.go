@@ -0,0 +1,246 @@
+package netviz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	colorPalette "image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+)
+
+// Delays of the frames of an animation in hundredths of a second
+const (
+	frameDelay    = 10  // Delay of a frame that covers exactly one time slice
+	maxFrameDelay = 500 // Longest delay, so idle periods don't stall the animation
+)
+
+// frame represents a single time slice of an animation
+type frame struct {
+	img     *image.RGBA
+	arrival int64 // Timestamp of the first packet in nanoseconds
+}
+
+// animationRenderer collects the images of all time slices as frames and
+// writes them into a single animated gif or png
+type animationRenderer struct {
+	cfg    configs
+	img    Image
+	rows   []Row
+	name   string // Name of the first image
+	frames []frame
+}
+
+func newAnimationRenderer(cfg configs) *animationRenderer {
+	return &animationRenderer{cfg: cfg}
+}
+
+func (a *animationRenderer) Begin(img Image) error {
+	a.img = img
+	a.rows = a.rows[:0]
+	return nil
+}
+
+func (a *animationRenderer) AddRow(r Row) error {
+	a.rows = append(a.rows, r)
+	return nil
+}
+
+func (a *animationRenderer) End() error {
+	scale := int(a.cfg.scale)
+
+	if len(a.rows) == 0 {
+		return nil
+	}
+	if len(a.frames) == 0 {
+		a.name = a.img.Name
+	}
+	a.frames = append(a.frames, frame{
+		img:     drawRows(a.img.Width*scale, a.img.Height*scale, a.rows, scale),
+		arrival: a.rows[0].Arrival,
+	})
+	return nil
+}
+
+// Close writes all frames into a single file
+func (a *animationRenderer) Close() error {
+	if len(a.frames) == 0 {
+		return nil
+	}
+
+	delays := frameDelays(a.frames, a.cfg.ts)
+	frames := make([]*image.RGBA, len(a.frames))
+	bounds := image.Rect(0, 0, 0, 0)
+	for _, f := range a.frames {
+		bounds = bounds.Union(f.img.Bounds())
+	}
+	// All frames share the size of the largest one
+	for i, f := range a.frames {
+		frames[i] = image.NewRGBA(bounds)
+		draw.Draw(frames[i], f.img.Bounds(), f.img, image.Point{}, draw.Src)
+	}
+
+	if a.cfg.format == "gif" {
+		return createGIF(a.name+".gif", frames, delays, a.cfg)
+	}
+	return createAPNG(a.name+".png", frames, delays, a.cfg)
+}
+
+// frameDelays returns the delay of each frame in hundredths of a second
+// proportional to the time until the next frame
+func frameDelays(frames []frame, ts int64) []int {
+	delays := make([]int, len(frames))
+
+	for i := range frames {
+		delay := frameDelay
+		if i+1 < len(frames) && ts > 0 {
+			delay = int(int64(frameDelay) * (frames[i+1].arrival - frames[i].arrival) / ts)
+		}
+		if delay < 1 {
+			delay = 1
+		} else if delay > maxFrameDelay {
+			delay = maxFrameDelay
+		}
+		delays[i] = delay
+	}
+	return delays
+}
+
+// paletted converts img into a paletted image. Colors are kept as they are
+// as long as they fit into a single palette.
+func paletted(img *image.RGBA) *image.Paletted {
+	var colors color.Palette
+	seen := make(map[color.RGBA]bool)
+
+	for i := 0; i+3 < len(img.Pix); i += 4 {
+		c := color.RGBA{R: img.Pix[i], G: img.Pix[i+1], B: img.Pix[i+2], A: img.Pix[i+3]}
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		colors = append(colors, c)
+		if len(colors) > 256 {
+			colors = append(color.Palette{color.RGBA{}}, colorPalette.WebSafe...)
+			break
+		}
+	}
+
+	p := image.NewPaletted(img.Bounds(), colors)
+	draw.Draw(p, img.Bounds(), img, image.Point{}, draw.Src)
+	return p
+}
+
+func createGIF(filename string, frames []*image.RGBA, delays []int, cfg configs) error {
+	var buf bytes.Buffer
+
+	anim := gif.GIF{}
+	for i, f := range frames {
+		anim.Image = append(anim.Image, paletted(f))
+		anim.Delay = append(anim.Delay, delays[i])
+		anim.Disposal = append(anim.Disposal, gif.DisposalBackground)
+	}
+
+	if err := gif.EncodeAll(&buf, &anim); err != nil {
+		return fmt.Errorf("could not encode animation: %s", err.Error())
+	}
+
+	return writeAnimation(filename, buf.Bytes(), cfg)
+}
+
+// getImageData returns the content of the IHDR chunk and the concatenated
+// content of the IDAT chunks of an encoded png
+func getImageData(raw []byte) ([]byte, []byte) {
+	var header, content []byte
+
+	for pos := len(pngMagic); pos+8 <= len(raw); {
+		length := int(binary.BigEndian.Uint32(raw[pos : pos+4]))
+		chunkType := string(raw[pos+4 : pos+8])
+		if pos+12+length > len(raw) {
+			break
+		}
+		switch chunkType {
+		case "IHDR":
+			header = raw[pos+8 : pos+8+length]
+		case "IDAT":
+			content = append(content, raw[pos+8:pos+8+length]...)
+		}
+		pos += 12 + length
+	}
+	return header, content
+}
+
+// apngFrame is encoded with an alpha channel even if it is opaque, as the
+// data of all frames has to match the color type of the single IHDR
+type apngFrame struct {
+	*image.RGBA
+}
+
+func (apngFrame) Opaque() bool {
+	return false
+}
+
+func createAPNG(filename string, frames []*image.RGBA, delays []int, cfg configs) error {
+	var anim bytes.Buffer
+	var seq uint32
+	var first []byte
+
+	anim.Write(pngMagic)
+	for i, f := range frames {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, apngFrame{f}); err != nil {
+			return fmt.Errorf("could not encode image: %s", err.Error())
+		}
+		header, content := getImageData(buf.Bytes())
+
+		if i == 0 {
+			first = header
+			actl := make([]byte, 8)
+			binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+			anim.Write(createChunk("IHDR", header))
+			anim.Write(createChunk("acTL", actl))
+		} else if !bytes.Equal(header, first) {
+			return fmt.Errorf("could not encode frame %d: header differs from the first frame", i)
+		}
+
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(f.Bounds().Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(f.Bounds().Dy()))
+		binary.BigEndian.PutUint16(fctl[20:], uint16(delays[i]))
+		binary.BigEndian.PutUint16(fctl[22:], 100)
+		anim.Write(createChunk("fcTL", fctl))
+		seq++
+
+		if i == 0 {
+			anim.Write(createChunk("IDAT", content))
+			continue
+		}
+		fdat := make([]byte, 4, 4+len(content))
+		binary.BigEndian.PutUint32(fdat, seq)
+		anim.Write(createChunk("fdAT", append(fdat, content...)))
+		seq++
+	}
+	anim.Write(createChunk("IEND", nil))
+
+	return writeAnimation(filename, anim.Bytes(), cfg)
+}
+
+func writeAnimation(filename string, content []byte, cfg configs) error {
+	f, err := createFile(filename, cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write content: %s", err.Error())
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not close file %s: %s", filename, err.Error())
+	}
+	return nil
+}
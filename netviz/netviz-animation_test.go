@@ -0,0 +1,249 @@
+package netviz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestFrameDelays(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		arrivals []int64
+		ts       int64
+		delays   []int
+	}{
+		{name: "Single frame", arrivals: []int64{42}, ts: 100, delays: []int{frameDelay}},
+		{name: "Consecutive slices", arrivals: []int64{0, 100, 200}, ts: 100, delays: []int{frameDelay, frameDelay, frameDelay}},
+		{name: "Gap", arrivals: []int64{0, 350, 450}, ts: 100, delays: []int{35, frameDelay, frameDelay}},
+		{name: "Long gap", arrivals: []int64{0, 1000000}, ts: 100, delays: []int{maxFrameDelay, frameDelay}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var frames []frame
+			for _, a := range tc.arrivals {
+				frames = append(frames, frame{arrival: a})
+			}
+			delays := frameDelays(frames, tc.ts)
+			if fmt.Sprint(delays) != fmt.Sprint(tc.delays) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.delays, delays)
+			}
+		})
+	}
+}
+
+func TestPaletted(t *testing.T) {
+	t.Parallel()
+
+	few := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	few.SetRGBA(0, 0, color.RGBA{R: 0xCA, G: 0xFE, B: 0xC0, A: 0xFF})
+	many := image.NewRGBA(image.Rect(0, 0, 300, 1))
+	for x := 0; x < 300; x++ {
+		many.SetRGBA(x, 0, color.RGBA{R: uint8(x), G: uint8(x >> 8), A: 0xFF})
+	}
+
+	tests := []struct {
+		name   string
+		img    *image.RGBA
+		colors int
+		exact  bool
+	}{
+		{name: "Exact colors", img: few, colors: 2, exact: true},
+		{name: "Too many colors", img: many, colors: 217},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			p := paletted(tc.img)
+			if len(p.Palette) != tc.colors {
+				t.Fatalf("Expected %d colors \t Got: %d", tc.colors, len(p.Palette))
+			}
+			if !tc.exact {
+				return
+			}
+			for x := 0; x < tc.img.Bounds().Dx(); x++ {
+				r1, g1, b1, a1 := tc.img.At(x, 0).RGBA()
+				r2, g2, b2, a2 := p.At(x, 0).RGBA()
+				if r1 != r2 || g1 != g2 || b1 != b2 || a1 != a2 {
+					t.Fatalf("Expected: %v \t Got: %v", tc.img.At(x, 0), p.At(x, 0))
+				}
+			}
+		})
+	}
+}
+
+func TestAnimationRenderer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestAnimationRenderer")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(fmt.Sprintf("%s/file", dir), nil, 0644); err != nil {
+		t.Fatalf("Could not create file: %v", err)
+	}
+
+	slices := []struct {
+		img  Image
+		rows []Row
+	}{
+		{img: Image{Width: 1, Height: 1}, rows: []Row{{Arrival: 0, Pixels: []Pixel{{X: 0, Y: 0, R: 0xCA, G: 0xFE, B: 0xC0}}}}},
+		{img: Image{Width: 2, Height: 3}, rows: []Row{{Arrival: 200, Pixels: []Pixel{{X: 0, Y: 2, R: 0xFF}, {X: 1, Y: 2, G: 0xFF}}}}},
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		prefix string
+		frames int
+		err    string
+	}{
+		{name: "gif", format: "gif", prefix: fmt.Sprintf("%s/anim", dir), frames: 2},
+		{name: "apng", format: "apng", prefix: fmt.Sprintf("%s/anim", dir), frames: 2},
+		{name: "No frames", format: "gif", prefix: fmt.Sprintf("%s/empty", dir)},
+		{name: "Not writable", format: "gif", prefix: fmt.Sprintf("%s/file/anim", dir), frames: 2, err: "could not create directory"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			a := newAnimationRenderer(configs{bpP: 24, ts: 100, scale: 2, format: tc.format})
+			for i := 0; i < tc.frames; i++ {
+				img := slices[i].img
+				img.Name = fmt.Sprintf("%s-%d", tc.prefix, i)
+				if err := render(a, img, slices[i].rows); err != nil {
+					t.Fatalf("Expected no error, got: %v", err)
+				}
+			}
+			err := a.Close()
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			if tc.frames == 0 {
+				return
+			}
+
+			ext := ".gif"
+			if tc.format == "apng" {
+				ext = ".png"
+			}
+			raw, err := ioutil.ReadFile(tc.prefix + "-0" + ext)
+			if err != nil {
+				t.Fatalf("Could not read animation: %v", err)
+			}
+			if tc.format == "gif" {
+				anim, err := gif.DecodeAll(bytes.NewReader(raw))
+				if err != nil {
+					t.Fatalf("Could not decode animation: %v", err)
+				}
+				if len(anim.Image) != tc.frames {
+					t.Fatalf("Expected %d frames \t Got: %d", tc.frames, len(anim.Image))
+				}
+				if anim.Delay[0] != 20 || anim.Config.Width != 4 || anim.Config.Height != 6 {
+					t.Fatalf("Expected 20/4x6 \t Got: %d/%dx%d", anim.Delay[0], anim.Config.Width, anim.Config.Height)
+				}
+				return
+			}
+			// The first frame is the default image of an animated png
+			img, err := png.Decode(bytes.NewReader(raw))
+			if err != nil {
+				t.Fatalf("Could not decode animation: %v", err)
+			}
+			if r, g, b, _ := img.At(1, 1).RGBA(); uint8(r>>8) != 0xCA || uint8(g>>8) != 0xFE || uint8(b>>8) != 0xC0 {
+				t.Fatalf("Expected: r202g254b192 \t Got: r%dg%db%d", uint8(r>>8), uint8(g>>8), uint8(b>>8))
+			}
+			for _, chunk := range []string{"acTL", "fcTL", "fdAT"} {
+				if !bytes.Contains(raw, []byte(chunk)) {
+					t.Fatalf("Expected %s chunk", chunk)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateAPNG(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestCreateAPNG")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	// An opaque frame followed by one with a transparent pixel
+	opaque := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	opaque.SetRGBA(0, 0, color.RGBA{R: 0xCA, G: 0xFE, B: 0xC0, A: 0xFF})
+	opaque.SetRGBA(1, 0, color.RGBA{R: 0x42, A: 0xFF})
+	translucent := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	translucent.SetRGBA(0, 0, color.RGBA{G: 0xFF, A: 0xFF})
+	frames := []*image.RGBA{opaque, translucent, opaque}
+
+	filename := fmt.Sprintf("%s/anim.png", dir)
+	if err := createAPNG(filename, frames, []int{1, 1, 1}, configs{}); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	raw, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("Could not read animation: %v", err)
+	}
+
+	// Split the animation into its IHDR and the image data of each frame
+	var header []byte
+	var data [][]byte
+	for pos := len(pngMagic); pos+8 <= len(raw); {
+		length := int(binary.BigEndian.Uint32(raw[pos : pos+4]))
+		content := raw[pos+8 : pos+8+length]
+		switch string(raw[pos+4 : pos+8]) {
+		case "IHDR":
+			header = content
+		case "IDAT":
+			data = append(data, content)
+		case "fdAT":
+			data = append(data, content[4:])
+		}
+		pos += 12 + length
+	}
+	if len(data) != len(frames) {
+		t.Fatalf("Expected %d frames \t Got: %d", len(frames), len(data))
+	}
+
+	for i, f := range frames {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, apngFrame{f}); err != nil {
+			t.Fatalf("Could not encode frame %d: %v", i, err)
+		}
+		if frameHeader, _ := getImageData(buf.Bytes()); !bytes.Equal(frameHeader, header) {
+			t.Fatalf("Frame %d - Expected IHDR: %v \t Got: %v", i, header, frameHeader)
+		}
+
+		// Each frame has to decode with the IHDR of the animation
+		single := append([]byte{}, pngMagic...)
+		single = append(single, createChunk("IHDR", header)...)
+		single = append(single, createChunk("IDAT", data[i])...)
+		single = append(single, createChunk("IEND", nil)...)
+		img, err := png.Decode(bytes.NewReader(single))
+		if err != nil {
+			t.Fatalf("Could not decode frame %d: %v", i, err)
+		}
+		for x := 0; x < 2; x++ {
+			if expected, got := color.NRGBAModel.Convert(f.At(x, 0)), color.NRGBAModel.Convert(img.At(x, 0)); expected != got {
+				t.Fatalf("Frame %d - Expected: %v \t Got: %v", i, expected, got)
+			}
+		}
+	}
+}
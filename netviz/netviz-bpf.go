@@ -0,0 +1,25 @@
+//go:build !nolibpcap
+// +build !nolibpcap
+
+package netviz
+
+import (
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcap"
+)
+
+// bpfSnaplen is the capture length filters of offline captures are compiled for
+const bpfSnaplen = 262144
+
+// newPacketFilter compiles expr with libpcap for packets of linkType, so
+// filters of files support the full syntax of pcap-filter(7)
+func newPacketFilter(expr string, linkType layers.LinkType) (packetFilter, error) {
+	bpf, err := pcap.NewBPF(linkType, bpfSnaplen, expr)
+	if err != nil {
+		return nil, err
+	}
+	return func(packet gopacket.Packet) bool {
+		return bpf.Matches(packet.Metadata().CaptureInfo, packet.Data())
+	}, nil
+}
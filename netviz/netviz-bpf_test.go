@@ -0,0 +1,65 @@
+//go:build !nolibpcap
+// +build !nolibpcap
+
+package netviz
+
+import (
+	"net"
+	"regexp"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestNewPacketFilter(t *testing.T) {
+	ip := func(protocol layers.IPProtocol) *layers.IPv4 {
+		return &layers.IPv4{Version: 4, IHL: 5, TTL: 64, Protocol: protocol, SrcIP: net.IP{10, 0, 0, 1}, DstIP: net.IP{192, 168, 1, 2}}
+	}
+	ether := &layers.Ethernet{SrcMAC: macA, DstMAC: macB, EthernetType: layers.EthernetTypeIPv4}
+
+	packets := [][]byte{
+		serialize(ether, ip(layers.IPProtocolTCP), &layers.TCP{SrcPort: 1234, DstPort: 80, DataOffset: 5, SYN: true}),
+		serialize(ether, ip(layers.IPProtocolUDP), &layers.UDP{SrcPort: 5353, DstPort: 53}),
+		serialize(ether, ip(layers.IPProtocolICMPv4), &layers.ICMPv4{TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoRequest, 0)}),
+		arpPacket,
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+		match  []bool // Expected result for the tcp, udp, icmp and arp packet
+		err    string
+	}{
+		{name: "Protocol", filter: "tcp", match: []bool{true, false, false, false}},
+		{name: "TCP flags", filter: "tcp[13] & 2 != 0", match: []bool{true, false, false, false}},
+		{name: "ICMP type", filter: "icmp[0] == 8", match: []bool{false, false, true, false}},
+		{name: "IP protocol", filter: "ip proto 17", match: []bool{false, true, false, false}},
+		{name: "Ether protocol", filter: "ether proto 0x0800", match: []bool{true, true, true, false}},
+		{name: "Vlan", filter: "vlan 100 and arp", match: []bool{false, false, false, true}},
+		{name: "Invalid filter", filter: "tcp and", err: "syntax error"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newPacketFilter(tc.filter, layers.LinkTypeEthernet)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			for i, data := range packets {
+				packet := gopacket.NewPacket(data, layers.LayerTypeEthernet, gopacket.Default)
+				packet.Metadata().CaptureInfo = gopacket.CaptureInfo{CaptureLength: len(data), Length: len(data)}
+				if match := f(packet); match != tc.match[i] {
+					t.Fatalf("Packet %d - Expected: %v \t Got: %v", i, tc.match[i], match)
+				}
+			}
+		})
+	}
+}
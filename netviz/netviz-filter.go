@@ -0,0 +1,429 @@
+//go:build nolibpcap
+// +build nolibpcap
+
+package netviz
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// filterProtocols holds the protocols a filter expression can refer to
+var filterProtocols = map[string]gopacket.LayerType{
+	"ether": layers.LayerTypeEthernet,
+	"vlan":  layers.LayerTypeDot1Q,
+	"arp":   layers.LayerTypeARP,
+	"ip":    layers.LayerTypeIPv4,
+	"ip6":   layers.LayerTypeIPv6,
+	"icmp":  layers.LayerTypeICMPv4,
+	"icmp6": layers.LayerTypeICMPv6,
+	"tcp":   layers.LayerTypeTCP,
+	"udp":   layers.LayerTypeUDP,
+	"sctp":  layers.LayerTypeSCTP,
+}
+
+// qualifiers of a primitive within a filter expression
+type qualifiers struct {
+	proto string // Protocol like tcp or ip6
+	dir   string // src, dst or empty for both directions
+	kind  string // host, net, port or portrange
+}
+
+// filterParser turns a filter expression in the syntax of pcap-filter(7)
+// into a packetFilter without the need of libpcap
+type filterParser struct {
+	tokens []string
+	pos    int
+	last   qualifiers // Qualifiers of the previous primitive, that apply to following bare ids
+}
+
+// newPacketFilter returns a packetFilter for expr, as libpcap is not available
+// in nolibpcap builds. It supports only the primitives host, net, port,
+// portrange, less, greater, vlan and protocol names with the qualifiers src
+// and dst, combined by and, or, not and parentheses.
+func newPacketFilter(expr string, linkType layers.LinkType) (packetFilter, error) {
+	p := &filterParser{tokens: tokenizeFilter(expr)}
+	if len(p.tokens) == 0 {
+		return func(packet gopacket.Packet) bool { return true }, nil
+	}
+
+	f, err := p.or()
+	if err == nil && p.pos < len(p.tokens) {
+		err = p.unexpected()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: filter is unsupported in nolibpcap builds", err.Error())
+	}
+	return f, nil
+}
+
+// tokenizeFilter splits expr into its tokens
+func tokenizeFilter(expr string) []string {
+	var tokens []string
+	var current strings.Builder
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for i := 0; i < len(expr); i++ {
+		switch {
+		case expr[i] == ' ' || expr[i] == '\t' || expr[i] == '\n':
+			flush()
+		case expr[i] == '(' || expr[i] == ')':
+			flush()
+			tokens = append(tokens, expr[i:i+1])
+		case expr[i] == '!':
+			flush()
+			tokens = append(tokens, "not")
+		case strings.HasPrefix(expr[i:], "&&"):
+			flush()
+			tokens = append(tokens, "and")
+			i++
+		case strings.HasPrefix(expr[i:], "||"):
+			flush()
+			tokens = append(tokens, "or")
+			i++
+		default:
+			current.WriteByte(expr[i])
+		}
+	}
+	flush()
+	return tokens
+}
+
+func (p *filterParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *filterParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *filterParser) unexpected() error {
+	if p.pos >= len(p.tokens) {
+		return fmt.Errorf("syntax error: unexpected end of filter")
+	}
+	return fmt.Errorf("syntax error: unexpected %s", p.tokens[p.pos])
+}
+
+// endOfPrimitive reports whether tok terminates a primitive
+func endOfPrimitive(tok string) bool {
+	return tok == "" || tok == "and" || tok == "or" || tok == ")"
+}
+
+func (p *filterParser) or() (packetFilter, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "or" {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(packet gopacket.Packet) bool { return l(packet) || r(packet) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) and() (packetFilter, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == "and" {
+		p.pos++
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(packet gopacket.Packet) bool { return l(packet) && r(packet) }
+	}
+	return left, nil
+}
+
+func (p *filterParser) unary() (packetFilter, error) {
+	switch tok := p.peek(); {
+	case tok == "not":
+		p.pos++
+		f, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(packet gopacket.Packet) bool { return !f(packet) }, nil
+	case tok == "(":
+		p.pos++
+		f, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, p.unexpected()
+		}
+		p.pos++
+		return f, nil
+	case endOfPrimitive(tok):
+		return nil, p.unexpected()
+	}
+	return p.primitive()
+}
+
+func (p *filterParser) primitive() (packetFilter, error) {
+	var q qualifiers
+	start := p.pos
+
+	switch p.peek() {
+	case "less", "greater":
+		cmp := p.next()
+		n, err := strconv.Atoi(p.peek())
+		if err != nil {
+			return nil, p.unexpected()
+		}
+		p.pos++
+		return func(packet gopacket.Packet) bool {
+			length := packet.Metadata().Length
+			if length == 0 {
+				length = len(packet.Data())
+			}
+			if cmp == "less" {
+				return length <= n
+			}
+			return length >= n
+		}, nil
+	}
+
+	for {
+		tok := p.peek()
+		if _, ok := filterProtocols[tok]; ok && p.pos == start {
+			q.proto = tok
+		} else if (tok == "src" || tok == "dst") && q.dir == "" {
+			q.dir = tok
+		} else if tok == "host" || tok == "net" || tok == "port" || tok == "portrange" {
+			q.kind = tok
+			p.pos++
+			break
+		} else {
+			break
+		}
+		p.pos++
+	}
+
+	if p.pos == start {
+		// A bare id reuses the qualifiers of the previous primitive
+		q = p.last
+		if len(q.kind) == 0 {
+			q.kind = "host"
+		}
+	} else if len(q.kind) == 0 {
+		if len(q.dir) == 0 {
+			if q.proto == "vlan" && !endOfPrimitive(p.peek()) {
+				return p.vlan()
+			}
+			layerType := filterProtocols[q.proto]
+			return func(packet gopacket.Packet) bool { return packet.Layer(layerType) != nil }, nil
+		}
+		q.kind = "host"
+	}
+
+	if endOfPrimitive(p.peek()) {
+		return nil, p.unexpected()
+	}
+	p.last = q
+
+	switch q.kind {
+	case "host":
+		return p.host(q)
+	case "net":
+		return p.net(q)
+	default:
+		return p.port(q)
+	}
+}
+
+func (p *filterParser) vlan() (packetFilter, error) {
+	id, err := strconv.ParseUint(p.peek(), 10, 12)
+	if err != nil {
+		return nil, p.unexpected()
+	}
+	p.pos++
+	return func(packet gopacket.Packet) bool {
+		for _, l := range packet.Layers() {
+			if vlan, ok := l.(*layers.Dot1Q); ok && uint64(vlan.VLANIdentifier) == id {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// matchDir reports whether src or dst matches with respect to the direction dir
+func matchDir(dir string, src, dst bool) bool {
+	return (dir != "dst" && src) || (dir != "src" && dst)
+}
+
+// ipEndpoints returns the source and destination addresses of packet for proto
+func ipEndpoints(packet gopacket.Packet, proto string) [][2]net.IP {
+	var endpoints [][2]net.IP
+
+	if ip, ok := packet.Layer(layers.LayerTypeIPv4).(*layers.IPv4); ok && (proto == "" || proto == "ip") {
+		endpoints = append(endpoints, [2]net.IP{ip.SrcIP, ip.DstIP})
+	}
+	if ip, ok := packet.Layer(layers.LayerTypeIPv6).(*layers.IPv6); ok && (proto == "" || proto == "ip6") {
+		endpoints = append(endpoints, [2]net.IP{ip.SrcIP, ip.DstIP})
+	}
+	if arp, ok := packet.Layer(layers.LayerTypeARP).(*layers.ARP); ok && (proto == "" || proto == "arp") {
+		endpoints = append(endpoints, [2]net.IP{arp.SourceProtAddress, arp.DstProtAddress})
+	}
+	return endpoints
+}
+
+// portEndpoints returns the source and destination ports of packet for proto
+func portEndpoints(packet gopacket.Packet, proto string) [][2]int {
+	var endpoints [][2]int
+
+	if tcp, ok := packet.Layer(layers.LayerTypeTCP).(*layers.TCP); ok && (proto == "" || proto == "tcp") {
+		endpoints = append(endpoints, [2]int{int(tcp.SrcPort), int(tcp.DstPort)})
+	}
+	if udp, ok := packet.Layer(layers.LayerTypeUDP).(*layers.UDP); ok && (proto == "" || proto == "udp") {
+		endpoints = append(endpoints, [2]int{int(udp.SrcPort), int(udp.DstPort)})
+	}
+	if sctp, ok := packet.Layer(layers.LayerTypeSCTP).(*layers.SCTP); ok && (proto == "" || proto == "sctp") {
+		endpoints = append(endpoints, [2]int{int(sctp.SrcPort), int(sctp.DstPort)})
+	}
+	return endpoints
+}
+
+func (p *filterParser) host(q qualifiers) (packetFilter, error) {
+	id := p.peek()
+
+	if q.proto == "ether" {
+		mac, err := net.ParseMAC(id)
+		if err != nil {
+			return nil, fmt.Errorf("syntax error: invalid ethernet address %s", id)
+		}
+		p.pos++
+		return func(packet gopacket.Packet) bool {
+			eth, ok := packet.Layer(layers.LayerTypeEthernet).(*layers.Ethernet)
+			return ok && matchDir(q.dir, bytes.Equal(eth.SrcMAC, mac), bytes.Equal(eth.DstMAC, mac))
+		}, nil
+	}
+
+	switch q.proto {
+	case "", "ip", "ip6", "arp":
+	default:
+		return nil, fmt.Errorf("syntax error: %s can't be combined with host", q.proto)
+	}
+
+	ip := net.ParseIP(id)
+	if ip == nil {
+		return nil, fmt.Errorf("syntax error: invalid host %s", id)
+	}
+	p.pos++
+	return func(packet gopacket.Packet) bool {
+		for _, e := range ipEndpoints(packet, q.proto) {
+			if matchDir(q.dir, ip.Equal(e[0]), ip.Equal(e[1])) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+func (p *filterParser) net(q qualifiers) (packetFilter, error) {
+	id := p.peek()
+
+	switch q.proto {
+	case "", "ip", "ip6", "arp":
+	default:
+		return nil, fmt.Errorf("syntax error: %s can't be combined with net", q.proto)
+	}
+
+	_, network, err := net.ParseCIDR(id)
+	if err != nil {
+		ip := net.ParseIP(id)
+		if ip == nil {
+			return nil, fmt.Errorf("syntax error: invalid net %s", id)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip, bits = ip.To4(), 8*net.IPv4len
+		}
+		network = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+	}
+	p.pos++
+	return func(packet gopacket.Packet) bool {
+		for _, e := range ipEndpoints(packet, q.proto) {
+			if matchDir(q.dir, network.Contains(e[0]), network.Contains(e[1])) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// parsePort returns the number of the port with the name or number id
+func parsePort(id, proto string) (int, error) {
+	if port, err := strconv.ParseUint(id, 10, 16); err == nil {
+		return int(port), nil
+	}
+	if len(proto) == 0 {
+		proto = "tcp"
+	}
+	return net.LookupPort(proto, id)
+}
+
+func (p *filterParser) port(q qualifiers) (packetFilter, error) {
+	id := p.peek()
+
+	switch q.proto {
+	case "", "tcp", "udp", "sctp":
+	default:
+		return nil, fmt.Errorf("syntax error: %s can't be combined with %s", q.proto, q.kind)
+	}
+
+	bounds := []string{id, id}
+	if q.kind == "portrange" {
+		bounds = strings.SplitN(id, "-", 2)
+		if len(bounds) != 2 {
+			return nil, fmt.Errorf("syntax error: invalid portrange %s", id)
+		}
+	}
+	low, err := parsePort(bounds[0], q.proto)
+	if err != nil {
+		return nil, fmt.Errorf("syntax error: invalid %s %s", q.kind, id)
+	}
+	high, err := parsePort(bounds[1], q.proto)
+	if err != nil {
+		return nil, fmt.Errorf("syntax error: invalid %s %s", q.kind, id)
+	}
+	if low > high {
+		low, high = high, low
+	}
+	p.pos++
+	return func(packet gopacket.Packet) bool {
+		for _, e := range portEndpoints(packet, q.proto) {
+			if matchDir(q.dir, e[0] >= low && e[0] <= high, e[1] >= low && e[1] <= high) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
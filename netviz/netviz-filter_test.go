@@ -0,0 +1,80 @@
+//go:build nolibpcap
+// +build nolibpcap
+
+package netviz
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestNewPacketFilter(t *testing.T) {
+	packets := []gopacket.Packet{
+		gopacket.NewPacket(tcpPacket, layers.LayerTypeEthernet, gopacket.Default),
+		gopacket.NewPacket(udpPacket, layers.LayerTypeEthernet, gopacket.Default),
+		gopacket.NewPacket(arpPacket, layers.LayerTypeEthernet, gopacket.Default),
+	}
+
+	tests := []struct {
+		name   string
+		filter string
+		match  []bool // Expected result for the tcp, udp and arp packet
+		err    string
+	}{
+		{name: "Empty", filter: "", match: []bool{true, true, true}},
+		{name: "Protocol", filter: "tcp", match: []bool{true, false, false}},
+		{name: "Protocols", filter: "ip6 or arp", match: []bool{false, true, true}},
+		{name: "Host", filter: "host 10.0.0.1", match: []bool{true, false, true}},
+		{name: "Source host", filter: "src host 192.168.1.2", match: []bool{false, false, false}},
+		{name: "Destination host", filter: "dst 192.168.1.2", match: []bool{true, false, false}},
+		{name: "IPv6 host", filter: "ip6 host 2001:db8::2", match: []bool{false, true, false}},
+		{name: "ARP host", filter: "arp dst host 10.0.0.2", match: []bool{false, false, true}},
+		{name: "Ethernet", filter: "ether src 00:11:22:33:44:55", match: []bool{true, false, true}},
+		{name: "Net", filter: "net 10.0.0.0/8", match: []bool{true, false, true}},
+		{name: "IPv6 net", filter: "dst net 2001:db8::/32", match: []bool{false, true, false}},
+		{name: "Port", filter: "port 80", match: []bool{true, false, false}},
+		{name: "Protocol and destination port", filter: "udp dst port 53", match: []bool{false, true, false}},
+		{name: "Other protocol", filter: "udp port 80", match: []bool{false, false, false}},
+		{name: "Portrange", filter: "src portrange 1000-6000", match: []bool{true, true, false}},
+		{name: "Bare ids", filter: "port 53 or 80", match: []bool{true, true, false}},
+		{name: "Vlan", filter: "vlan 100", match: []bool{false, false, true}},
+		{name: "Less", filter: "less 61", match: []bool{true, false, true}},
+		{name: "Greater", filter: "greater 61", match: []bool{false, true, false}},
+		{name: "Not", filter: "not tcp", match: []bool{false, true, true}},
+		{name: "Symbols", filter: "!(tcp||udp) && ether", match: []bool{false, false, true}},
+		{name: "Precedence", filter: "arp or ip and port 80", match: []bool{true, false, true}},
+		{name: "Parentheses", filter: "(arp or ip) and port 80", match: []bool{true, false, false}},
+		{name: "Unknown host", filter: "noFilter", err: "syntax error"},
+		{name: "Missing id", filter: "port", err: "syntax error: unexpected end of filter"},
+		{name: "Missing parenthesis", filter: "(tcp or udp", err: "syntax error"},
+		{name: "Trailing token", filter: "tcp udp", err: "syntax error: unexpected udp"},
+		{name: "Invalid combination", filter: "icmp port 80", err: "can't be combined"},
+		{name: "Invalid portrange", filter: "portrange 80", err: "invalid portrange"},
+		{name: "Byte offset", filter: "tcp[13] & 2 != 0", err: "unsupported in nolibpcap builds"},
+		{name: "Protocol number", filter: "ip proto 17", err: "unsupported in nolibpcap builds"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newPacketFilter(tc.filter, layers.LinkTypeEthernet)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			for i, packet := range packets {
+				if match := f(packet); match != tc.match[i] {
+					t.Fatalf("Packet %d - Expected: %v \t Got: %v", i, tc.match[i], match)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package netviz
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/google/gopacket"
+)
+
+// htmlInformation represents a single entry of the metadata of a html report
+type htmlInformation struct {
+	Key   string
+	Value string
+}
+
+// htmlRow represents a single packet within a html report
+type htmlRow struct {
+	Index   uint   `json:"index"`
+	Arrival int64  `json:"toa"`
+	CapLen  int    `json:"caplen"`
+	Len     int    `json:"len"`
+	Y       int    `json:"y"`
+	Data    string `json:"data"`    // Hex of the visualized bytes
+	Headers []int  `json:"headers"` // End of the link, network and transport layer header
+	Pixels  string `json:"pixels"`  // Hex of the colors of the pixels
+}
+
+// htmlReport holds the data of a html report, that is embedded as JSON
+type htmlReport struct {
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Scale        uint      `json:"scale"`
+	BitsPerPixel uint      `json:"bitsPerPixel"`
+	Layers       []string  `json:"layers"`
+	Rows         []htmlRow `json:"rows"`
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>goNetViz {{.Name}}</title>
+<style>
+body { margin: 0; display: flex; height: 100vh; font-family: monospace; font-size: 12px; }
+#view { flex: 1; position: relative; overflow: hidden; background: #404040; cursor: crosshair; }
+#canvas { position: absolute; top: 0; left: 0; }
+#hover { position: absolute; display: none; pointer-events: none; padding: 4px; background: rgba(255, 255, 255, 0.9); white-space: pre; }
+#panel { width: 360px; padding: 8px; overflow: auto; border-left: 1px solid #808080; }
+td { padding: 2px 6px; vertical-align: top; word-break: break-all; }
+</style>
+</head>
+<body>
+<div id="view"><canvas id="canvas"></canvas><div id="hover"></div></div>
+<div id="panel">
+<h3>goNetViz</h3>
+<table>
+{{- range .Information}}
+<tr><td>{{.Key}}</td><td>{{.Value}}</td></tr>
+{{- end}}
+</table>
+<h3>Packet</h3>
+<pre id="packet">Click on a pixel to select its packet.</pre>
+<p>Scroll to zoom, drag to pan.</p>
+</div>
+<script type="application/json" id="metadata">{{.Metadata}}</script>
+<script>
+const report = {{.Report}};
+const image = document.createElement("canvas");
+const rows = {};
+image.width = report.width;
+image.height = report.height;
+const pixels = image.getContext("2d").createImageData(report.width, report.height);
+for (const row of report.rows) {
+	rows[row.y] = row;
+	for (let x = 0; x * 6 < row.pixels.length; x++) {
+		const i = (row.y * report.width + x) * 4;
+		for (let c = 0; c < 3; c++) {
+			pixels.data[i + c] = parseInt(row.pixels.substr(x * 6 + c * 2, 2), 16);
+		}
+		pixels.data[i + 3] = 255;
+	}
+}
+image.getContext("2d").putImageData(pixels, 0, 0);
+
+const view = document.getElementById("view");
+const canvas = document.getElementById("canvas");
+const hover = document.getElementById("hover");
+const packet = document.getElementById("packet");
+let zoom = report.scale, panX = 0, panY = 0, drag = null;
+
+function draw() {
+	canvas.width = view.clientWidth;
+	canvas.height = view.clientHeight;
+	const ctx = canvas.getContext("2d");
+	ctx.imageSmoothingEnabled = false;
+	ctx.setTransform(zoom, 0, 0, zoom, panX, panY);
+	ctx.drawImage(image, 0, 0);
+}
+
+function position(e) {
+	const r = view.getBoundingClientRect();
+	return [e.clientX - r.left, e.clientY - r.top];
+}
+
+function layer(row, offset) {
+	if (!row.headers) {
+		return "unknown";
+	}
+	for (let i = 0; i < row.headers.length; i++) {
+		if (offset < row.headers[i]) {
+			return report.layers[i];
+		}
+	}
+	return report.layers[report.layers.length - 1];
+}
+
+function describe(mx, my) {
+	const x = Math.floor((mx - panX) / zoom), y = Math.floor((my - panY) / zoom);
+	const row = rows[y];
+	if (!row || x < 0 || x * 6 >= row.pixels.length) {
+		return null;
+	}
+	const offset = Math.floor(x * report.bitsPerPixel / 8);
+	const value = row.data.substr(offset * 2, 2);
+	return {row: row, text: "Packet " + row.index + "\nArrival " + new Date(row.toa / 1000000).toISOString() +
+		"\nOffset " + offset + "\nByte " + (value ? "0x" + value : "-") + "\nLayer " + layer(row, offset)};
+}
+
+view.addEventListener("mousedown", e => {
+	const [mx, my] = position(e);
+	drag = {x: mx, y: my, panX: panX, panY: panY, moved: false};
+});
+view.addEventListener("mouseup", e => {
+	if (drag && !drag.moved) {
+		const d = describe(...position(e));
+		if (d) {
+			const bytes = d.row.data.match(/.{1,32}/g) || [];
+			packet.textContent = d.text + "\nLength " + d.row.caplen + " of " + d.row.len + " bytes\n\n" +
+				bytes.map((line, i) => (i * 16).toString(16).padStart(8, "0") + "  " + line.replace(/(..)/g, "$1 ")).join("\n");
+		}
+	}
+	drag = null;
+});
+view.addEventListener("mouseleave", () => {
+	drag = null;
+	hover.style.display = "none";
+});
+view.addEventListener("mousemove", e => {
+	const [mx, my] = position(e);
+	if (drag) {
+		drag.moved = drag.moved || Math.abs(mx - drag.x) + Math.abs(my - drag.y) > 2;
+		panX = drag.panX + mx - drag.x;
+		panY = drag.panY + my - drag.y;
+		draw();
+		return;
+	}
+	const d = describe(mx, my);
+	if (!d) {
+		hover.style.display = "none";
+		return;
+	}
+	hover.textContent = d.text;
+	hover.style.left = (mx + 12) + "px";
+	hover.style.top = (my + 12) + "px";
+	hover.style.display = "block";
+});
+view.addEventListener("wheel", e => {
+	e.preventDefault();
+	const [mx, my] = position(e);
+	const f = e.deltaY < 0 ? 1.25 : 0.8;
+	panX = mx - (mx - panX) * f;
+	panY = my - (my - panY) * f;
+	zoom *= f;
+	draw();
+}, {passive: false});
+window.addEventListener("resize", draw);
+draw();
+</script>
+</body>
+</html>
+`))
+
+func createHTML(filename string, width, height int, rows []Row, cfg configs) error {
+	var buf bytes.Buffer
+	var pixels bytes.Buffer
+
+	if len(rows) == 0 {
+		return fmt.Errorf("no content to write")
+	}
+
+	layer, err := getLayer(cfg.layer)
+	if err != nil {
+		return err
+	}
+
+	report := htmlReport{Width: width, Height: height, Scale: cfg.scale, BitsPerPixel: cfg.bpP, Layers: protocolLayers}
+	for _, r := range rows {
+		if len(r.Pixels) == 0 {
+			continue
+		}
+		pixels.Reset()
+		for _, p := range r.Pixels {
+			pixels.Write([]byte{p.R, p.G, p.B})
+		}
+		row := htmlRow{Index: r.Index, Arrival: r.Arrival, CapLen: r.CapLen, Len: r.Len, Y: r.Pixels[0].Y,
+			Data: hex.EncodeToString(r.Data), Pixels: hex.EncodeToString(pixels.Bytes())}
+		// Protocol layers can't be decoded after a logical operation
+		if cfg.logicOp.name == "none" || cfg.logicOp.name == "" {
+			row.Headers = getHeaders(gopacket.NewPacket(r.Data, getDecoder(layer, cfg.linkType, r.Data), gopacket.Default))
+		}
+		report.Rows = append(report.Rows, row)
+	}
+
+	information := []htmlInformation{
+		{Key: "goNetViz", Value: Version},
+		{Key: "Scale", Value: fmt.Sprintf("%d", cfg.scale)},
+		{Key: "BitsPerPixel", Value: fmt.Sprintf("%d", cfg.bpP)},
+		{Key: "DTG", Value: time.Now().UTC().String()},
+		{Key: "Source", Value: cfg.input},
+		{Key: "Filter", Value: cfg.filter},
+		{Key: "LogicGate", Value: cfg.logicOp.name},
+		{Key: "LogicValue", Value: fmt.Sprintf("0x%X", cfg.logicOp.value)},
+		{Key: "LayerTint", Value: strconv.FormatBool((cfg.flags & layerTint) == layerTint)},
+		{Key: "Layer", Value: cfg.layer},
+	}
+
+	if err := htmlTemplate.Execute(&buf, struct {
+		Name        string
+		Information []htmlInformation
+		Report      htmlReport
+		Metadata    metadata
+	}{Name: filename, Information: information, Report: report, Metadata: newMetadata(cfg, rows)}); err != nil {
+		return fmt.Errorf("could not create report: %s", err.Error())
+	}
+
+	f, err := createFile(filename, cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write content: %s", err.Error())
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not close file %s: %s", filename, err.Error())
+	}
+	return nil
+}
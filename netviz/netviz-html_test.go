@@ -0,0 +1,70 @@
+package netviz
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestCreateHTML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestCreateHTML")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+	xor := logicOp{
+		name:  "xor",
+		gate:  nil,
+		value: 0xFF,
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		rows     []Row
+		cfg      configs
+		content  []string
+		err      string
+	}{
+		{name: "No Data", filename: fmt.Sprintf("%s/noData.html", dir), cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "html", logicOp: logic}, err: "no content to write"},
+		{name: "Just directory name", filename: dir, rows: []Row{{Pixels: []Pixel{{X: 0, Y: 0, R: 0xCA, G: 0xFE, B: 0xC0}}}}, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "html", logicOp: logic}, err: "could not open file"},
+		{name: "Single pixel", filename: fmt.Sprintf("%s/single.html", dir), rows: []Row{{Index: 7, Arrival: 42, CapLen: 3, Len: 64, Data: []byte{0xCA, 0xFE, 0xC0}, Pixels: []Pixel{{X: 0, Y: 0, R: 0xCA, G: 0xFE, B: 0xC0}}}}, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", filter: "udp", format: "html", layer: "payload", logicOp: logic},
+			content: []string{"<td>Source</td><td>input</td>", "<td>Filter</td><td>udp</td>", "<td>LogicGate</td><td>none</td>", `"packets":[{"index":7,"row":0,"toa":42,"caplen":3,"len":64}]`, `"index":7,"toa":42,"caplen":3,"len":64,"y":0,"data":"cafec0","headers":[0,0,0],"pixels":"cafec0"`}},
+		{name: "Logic gate", filename: fmt.Sprintf("%s/xor.html", dir), rows: []Row{{Index: 1, CapLen: 1, Len: 1, Data: []byte{0x35}, Pixels: []Pixel{{X: 0, Y: 2, R: 0x35}}}}, cfg: configs{bpP: 24, flags: solder, scale: 2, xlimit: 1500, input: "input", format: "html", layer: "link", logicOp: xor},
+			content: []string{"<td>LogicValue</td><td>0xFF</td>", `"scale":2`, `"y":2,"data":"35","headers":null,"pixels":"350000"`}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := createHTML(tc.filename, 1, 3, tc.rows, tc.cfg)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			report, err := ioutil.ReadFile(tc.filename)
+			if err != nil {
+				t.Fatalf("Could not read report: %v", err)
+			}
+			for _, c := range tc.content {
+				if !strings.Contains(string(report), c) {
+					t.Fatalf("Expected %s in report", c)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+//go:build !nolibpcap
+// +build !nolibpcap
+
+package netviz
+
+import (
+	"fmt"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/pcap"
+)
+
+// liveHandle closes a libpcap handle as io.Closer
+type liveHandle struct {
+	*pcap.Handle
+}
+
+func (h liveHandle) Close() error {
+	h.Handle.Close()
+	return nil
+}
+
+// timeoutExpired reports whether err is caused by the read timeout of a network interface
+func timeoutExpired(err error) bool {
+	return err == pcap.NextErrorTimeoutExpired
+}
+
+func openLive(input string, capture captureOpts) (*pcap.Handle, error) {
+	inactive, err := pcap.NewInactiveHandle(input)
+	if err != nil {
+		return nil, err
+	}
+	defer inactive.CleanUp()
+
+	if err := inactive.SetSnapLen(capture.snaplen); err != nil {
+		return nil, fmt.Errorf("could not set snaplen %d: %s", capture.snaplen, err.Error())
+	}
+	if err := inactive.SetPromisc(capture.promisc); err != nil {
+		return nil, fmt.Errorf("could not set promiscuous mode: %s", err.Error())
+	}
+	if err := inactive.SetTimeout(capture.timeout); err != nil {
+		return nil, fmt.Errorf("could not set timeout %s: %s", capture.timeout, err.Error())
+	}
+	if capture.bufSize > 0 {
+		if err := inactive.SetBufferSize(capture.bufSize); err != nil {
+			return nil, fmt.Errorf("could not set buffer size %d: %s", capture.bufSize, err.Error())
+		}
+	}
+	if err := inactive.SetImmediateMode(capture.immediate); err != nil {
+		return nil, fmt.Errorf("could not set immediate mode: %s", err.Error())
+	}
+
+	return inactive.Activate()
+}
+
+// initLiveSource captures the packets of the network interface input with libpcap
+func initLiveSource(input, filter string, layer int, capture captureOpts) (Source, error) {
+	handle, err := openLive(input, capture)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(filter) != 0 {
+		err = handle.SetBPFFilter(filter)
+		if err != nil {
+			handle.Close()
+			return nil, fmt.Errorf("%s\nInvalid Filter: %s", err, filter)
+		}
+	}
+
+	src := gopacket.NewPacketSource(handle, handle.LinkType())
+	src.DecodeOptions = gopacket.Lazy
+
+	return pcapInput{closer: liveHandle{handle}, source: src, linkType: handle.LinkType(), layer: layer}, nil
+}
@@ -0,0 +1,142 @@
+package netviz
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+// metadata describes an image and its packets in a machine-readable way
+type metadata struct {
+	Version  string           `json:"version"`
+	DTG      string           `json:"dtg"`
+	LinkType layers.LinkType  `json:"linkType"` // Link type of the source
+	Config   metadataConfig   `json:"config"`
+	Packets  []packetMetadata `json:"packets"`
+}
+
+// metadataConfig holds the configuration an image was created with
+type metadataConfig struct {
+	BitsPerPixel    uint   `json:"bitsPerPixel"`
+	PacketsPerImage uint   `json:"packetsPerImage"`
+	Timeslize       int64  `json:"timeslize"`     // Nanoseconds per image
+	RowResolution   int64  `json:"rowResolution"` // Nanoseconds per row
+	RowGap          uint   `json:"rowGap"`
+	Count           uint   `json:"count"`
+	Scale           uint   `json:"scale"`
+	Limit           uint   `json:"limit"`
+	Snaplen         int    `json:"snaplen"`
+	Promisc         bool   `json:"promisc"`
+	Source          string `json:"source"`
+	Filter          string `json:"filter"`
+	Framing         string `json:"framing"`
+	Format          string `json:"format"`
+	Layer           string `json:"layer"`
+	LayerTint       bool   `json:"layerTint"`
+	LogicGate       string `json:"logicGate"`
+	LogicValue      byte   `json:"logicValue"`
+	Compact         bool   `json:"compact"`
+	Axes            bool   `json:"axes"`
+}
+
+// packetMetadata describes a single packet within an image
+type packetMetadata struct {
+	Index   uint  `json:"index"`
+	Row     int   `json:"row"`
+	Arrival int64 `json:"toa"` // Timestamp of arrival in nanoseconds
+	CapLen  int   `json:"caplen"`
+	Len     int   `json:"len"`
+}
+
+func newMetadata(cfg configs, rows []Row) metadata {
+	meta := metadata{
+		Version:  Version,
+		DTG:      time.Now().UTC().String(),
+		LinkType: cfg.linkType,
+		Config: metadataConfig{
+			BitsPerPixel:    cfg.bpP,
+			PacketsPerImage: cfg.ppI,
+			Timeslize:       cfg.ts,
+			RowResolution:   cfg.rowRes,
+			RowGap:          cfg.rowGap,
+			Count:           cfg.limit,
+			Scale:           cfg.scale,
+			Limit:           cfg.xlimit,
+			Snaplen:         cfg.snaplen,
+			Promisc:         cfg.promisc,
+			Source:          cfg.input,
+			Filter:          cfg.filter,
+			Framing:         cfg.framing,
+			Format:          cfg.format,
+			Layer:           cfg.layer,
+			LayerTint:       (cfg.flags & layerTint) == layerTint,
+			LogicGate:       cfg.logicOp.name,
+			LogicValue:      cfg.logicOp.value,
+			Compact:         (cfg.flags & compact) == compact,
+			Axes:            (cfg.flags & axes) == axes,
+		},
+		Packets: []packetMetadata{},
+	}
+
+	for _, r := range rows {
+		if len(r.Pixels) == 0 {
+			continue
+		}
+		meta.Packets = append(meta.Packets, packetMetadata{Index: r.Index, Row: r.Pixels[0].Y, Arrival: r.Arrival, CapLen: r.CapLen, Len: r.Len})
+	}
+	return meta
+}
+
+// encode returns meta as JSON. Characters with a special meaning in markup
+// are escaped, so the result can be embedded into svg and html.
+func (meta metadata) encode() (string, error) {
+	raw, err := json.Marshal(meta)
+	if err != nil {
+		return "", fmt.Errorf("could not encode metadata: %s", err.Error())
+	}
+	return string(raw), nil
+}
+
+// getMetadataOptions returns the options for reconstruction from embedded metadata
+func getMetadataOptions(raw string) (reconstructOptions, error) {
+	var options reconstructOptions
+	// Images without a link type have been created from Ethernet
+	meta := metadata{LinkType: layers.LinkTypeEthernet}
+
+	if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+		return options, fmt.Errorf("could not decode metadata: %s", err.Error())
+	}
+	if len(meta.Version) == 0 {
+		return options, fmt.Errorf("no goNetViz information found")
+	}
+
+	options.BpP = int(meta.Config.BitsPerPixel)
+	options.Scale = int(meta.Config.Scale)
+	options.Dtg = meta.DTG
+	options.Source = meta.Config.Source
+	options.Filter = meta.Config.Filter
+	options.LogicGate = meta.Config.LogicGate
+	options.LogicValue = int(meta.Config.LogicValue)
+	options.LayerTint = strconv.FormatBool(meta.Config.LayerTint)
+	options.Layer = meta.Config.Layer
+	options.LinkType = meta.LinkType
+	return options, nil
+}
+
+// getMetadataPackets returns the timestamp and lengths of each packet in
+// embedded metadata by the row it starts in
+func getMetadataPackets(raw string) (map[int]data, error) {
+	var meta metadata
+	info := make(map[int]data)
+
+	if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+		return info, fmt.Errorf("could not decode metadata: %s", err.Error())
+	}
+	for _, pkt := range meta.Packets {
+		info[pkt.Row] = data{toa: pkt.Arrival, len: pkt.CapLen, olen: pkt.Len}
+	}
+	return info, nil
+}
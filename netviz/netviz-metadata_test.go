@@ -0,0 +1,120 @@
+package netviz
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/google/gopacket/layers"
+)
+
+func TestNewMetadata(t *testing.T) {
+	t.Parallel()
+
+	cfg := configs{bpP: 24, ppI: 5, scale: 2, xlimit: 1500, flags: solder | compact, input: "eth0", filter: "len < 100", format: "svg", layer: "link", linkType: layers.LinkTypeLinuxSLL, logicOp: logicOp{name: "xor", value: 0x42}}
+	rows := []Row{
+		{Index: 3, Arrival: 42, CapLen: 3, Len: 64, Pixels: []Pixel{{X: 0, Y: 0}}},
+		{Index: 4, Arrival: 43},
+		{Index: 5, Arrival: 44, CapLen: 1, Len: 1, Pixels: []Pixel{{X: 0, Y: 1}}},
+	}
+
+	meta := newMetadata(cfg, rows)
+	raw, err := meta.encode()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if matched, _ := regexp.MatchString("[<>&]", raw); matched {
+		t.Fatalf("Expected markup characters to be escaped \t Got: %s", raw)
+	}
+
+	var decoded metadata
+	if err := json.Unmarshal([]byte(raw), &decoded); err != nil {
+		t.Fatalf("Could not decode metadata: %v", err)
+	}
+	if decoded.Version != Version || decoded.Config.Filter != "len < 100" || decoded.Config.LogicValue != 0x42 || !decoded.Config.Compact || decoded.Config.Axes {
+		t.Fatalf("Unexpected configuration: %+v", decoded.Config)
+	}
+	if decoded.LinkType != layers.LinkTypeLinuxSLL {
+		t.Fatalf("Expected: %v \t Got: %v", layers.LinkTypeLinuxSLL, decoded.LinkType)
+	}
+	expected := []packetMetadata{{Index: 3, Row: 0, Arrival: 42, CapLen: 3, Len: 64}, {Index: 5, Row: 1, Arrival: 44, CapLen: 1, Len: 1}}
+	if len(decoded.Packets) != len(expected) {
+		t.Fatalf("Expected: %v \t Got: %v", expected, decoded.Packets)
+	}
+	for i := range expected {
+		if decoded.Packets[i] != expected[i] {
+			t.Fatalf("Expected: %v \t Got: %v", expected, decoded.Packets)
+		}
+	}
+}
+
+func TestGetMetadataOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		raw     string
+		options reconstructOptions
+		err     string
+	}{
+		{name: "Valid", raw: `{"version":"0.0.5","dtg":"now","config":{"bitsPerPixel":3,"scale":2,"source":"eth0","filter":"udp","layer":"network","layerTint":true,"logicGate":"xor","logicValue":255}}`,
+			options: reconstructOptions{BpP: 3, Scale: 2, Dtg: "now", Source: "eth0", Filter: "udp", LogicGate: "xor", LogicValue: 255, LayerTint: "true", Layer: "network", LinkType: layers.LinkTypeEthernet}},
+		{name: "Link type", raw: `{"version":"0.0.5","dtg":"now","linkType":113,"config":{"bitsPerPixel":1,"scale":1}}`,
+			options: reconstructOptions{BpP: 1, Scale: 1, Dtg: "now", LayerTint: "false", LinkType: layers.LinkTypeLinuxSLL}},
+		{name: "Invalid JSON", raw: `{"version":`, err: "could not decode metadata"},
+		{name: "No version", raw: `{"config":{}}`, err: "no goNetViz information found"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := getMetadataOptions(tc.raw)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			if options != tc.options {
+				t.Fatalf("Expected: %+v \t Got: %+v", tc.options, options)
+			}
+		})
+	}
+}
+
+func TestGetMetadataPackets(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		packets map[int]data
+		err     string
+	}{
+		{name: "Packets", raw: `{"version":"0.0.5","packets":[{"index":3,"row":0,"toa":1257894000000000123,"caplen":3,"len":64},{"index":5,"row":2,"toa":44,"caplen":1,"len":1}]}`,
+			packets: map[int]data{0: {toa: 1257894000000000123, len: 3, olen: 64}, 2: {toa: 44, len: 1, olen: 1}}},
+		{name: "No packets", raw: `{"version":"0.0.5"}`, packets: map[int]data{}},
+		{name: "Invalid JSON", raw: `{"packets":`, err: "could not decode metadata"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			packets, err := getMetadataPackets(tc.raw)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			if !reflect.DeepEqual(packets, tc.packets) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.packets, packets)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+//go:build nolibpcap
+// +build nolibpcap
+
+package netviz
+
+import (
+	"fmt"
+)
+
+// timeoutExpired reports whether err is caused by the read timeout of a network interface
+func timeoutExpired(err error) bool {
+	return false
+}
+
+// initLiveSource fails, as capturing on network interfaces requires libpcap
+func initLiveSource(input, filter string, layer int, capture captureOpts) (Source, error) {
+	return nil, fmt.Errorf("could not open %s: capturing on network interfaces requires a build without the nolibpcap tag", input)
+}
@@ -0,0 +1,19 @@
+//go:build nolibpcap
+// +build nolibpcap
+
+package netviz
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestInitLiveSource(t *testing.T) {
+	_, err := initLiveSource("lo", "", 0, captureOpts{snaplen: 1500})
+	if err == nil {
+		t.Fatalf("Expected error, got none")
+	}
+	if matched, _ := regexp.MatchString("requires a build without the nolibpcap tag", err.Error()); matched == false {
+		t.Fatalf("Error matching regex: %v \t Got: %v", "requires a build without the nolibpcap tag", err)
+	}
+}
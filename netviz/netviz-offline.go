@@ -0,0 +1,150 @@
+package netviz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// pcapngMagic is the block type of the section header block every pcapng starts with
+var pcapngMagic = []byte{0x0A, 0x0D, 0x0D, 0x0A}
+
+// pcapMagics holds the magic numbers of pcap in both byte orders
+var pcapMagics = [][]byte{
+	{0xA1, 0xB2, 0xC3, 0xD4}, {0xD4, 0xC3, 0xB2, 0xA1}, // Microsecond resolution
+	{0xA1, 0xB2, 0x3C, 0x4D}, {0x4D, 0x3C, 0xB2, 0xA1}, // Nanosecond resolution
+}
+
+// getCaptureFormat returns pcap or pcapng depending on the magic number
+// magic starts with or an empty string for unknown formats
+func getCaptureFormat(magic []byte) string {
+	if bytes.HasPrefix(magic, pcapngMagic) {
+		return "pcapng"
+	}
+	for _, m := range pcapMagics {
+		if bytes.HasPrefix(magic, m) {
+			return "pcap"
+		}
+	}
+	return ""
+}
+
+// packetFilter reports whether packet matches a filter expression
+type packetFilter func(packet gopacket.Packet) bool
+
+// bufferedReadCloser reads through a buffer and closes the underlying reader
+type bufferedReadCloser struct {
+	*bufio.Reader
+	io.Closer
+}
+
+// streamResult is the outcome of a single read from a stream
+type streamResult struct {
+	data []byte
+	err  error
+}
+
+// streamReader reads from a stream in the background, so a read that waits
+// for more data of the stream doesn't delay the cancellation of ctx
+type streamReader struct {
+	ctx     context.Context
+	r       io.ReadCloser
+	pending chan streamResult // Result of the read in the background, if any
+}
+
+func newStreamReader(ctx context.Context, r io.ReadCloser) *streamReader {
+	return &streamReader{ctx: ctx, r: r}
+}
+
+func (s *streamReader) Read(p []byte) (int, error) {
+	if err := s.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if s.pending == nil {
+		buf := make([]byte, len(p))
+		s.pending = make(chan streamResult, 1)
+		go func(pending chan<- streamResult) {
+			n, err := s.r.Read(buf)
+			pending <- streamResult{data: buf[:n], err: err}
+		}(s.pending)
+	}
+
+	select {
+	case res := <-s.pending:
+		s.pending = nil
+		return copy(p, res.data), res.err
+	case <-s.ctx.Done():
+		return 0, s.ctx.Err()
+	}
+}
+
+func (s *streamReader) Close() error {
+	return s.r.Close()
+}
+
+// initStreamSource reads from a stream like stdin or a named pipe. Streams
+// that start with the magic number of pcap or pcapng are read as captures,
+// everything else as raw bytes. A framing always reads the stream as raw bytes.
+// Reading stops once ctx is canceled, even if the stream is still open.
+func initStreamSource(ctx context.Context, stream io.ReadCloser, name, filter string, pcap bool, layer int, framing framingOpts) (Source, error) {
+	r := newStreamReader(ctx, stream)
+	if len(framing.framing) != 0 {
+		return newRegularFile(r, framing), nil
+	}
+
+	input := bufferedReadCloser{Reader: bufio.NewReader(r), Closer: r}
+	magic, _ := input.Peek(len(pcapngMagic))
+
+	if pcap || len(filter) != 0 || len(getCaptureFormat(magic)) != 0 {
+		return initOfflineSource(input, name, filter, layer)
+	}
+	return newRegularFile(input, framing), nil
+}
+
+// initOfflineSource reads the packets of a pcap or pcapng from r without
+// the need of libpcap. r is closed together with the returned Source.
+func initOfflineSource(r io.ReadCloser, name, filter string, layer int) (Source, error) {
+	var data interface {
+		gopacket.PacketDataSource
+		LinkType() layers.LinkType
+	}
+	var err error
+	p := pcapInput{closer: r, layer: layer}
+
+	input := bufio.NewReader(r)
+	magic, _ := input.Peek(len(pcapngMagic))
+
+	switch getCaptureFormat(magic) {
+	case "pcapng":
+		data, err = pcapgo.NewNgReader(input, pcapgo.DefaultNgReaderOptions)
+	case "pcap":
+		data, err = pcapgo.NewReader(input)
+	default:
+		err = fmt.Errorf("unknown file format")
+	}
+	if err != nil {
+		r.Close()
+		return nil, fmt.Errorf("could not read %s: %s", name, err.Error())
+	}
+
+	p.linkType = data.LinkType()
+	if len(filter) != 0 {
+		p.filter, err = newPacketFilter(filter, p.linkType)
+		if err != nil {
+			r.Close()
+			return nil, fmt.Errorf("%s\nInvalid Filter: %s", err, filter)
+		}
+	}
+
+	src := gopacket.NewPacketSource(data, p.linkType)
+	src.DecodeOptions = gopacket.Lazy
+
+	p.source = src
+	return p, nil
+}
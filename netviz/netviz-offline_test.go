@@ -0,0 +1,283 @@
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// serialize returns the bytes of a packet consisting of l
+func serialize(l ...gopacket.SerializableLayer) []byte {
+	buf := gopacket.NewSerializeBuffer()
+	gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true}, l...)
+	return buf.Bytes()
+}
+
+var (
+	macA = net.HardwareAddr{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}
+	macB = net.HardwareAddr{0x66, 0x77, 0x88, 0x99, 0xAA, 0xBB}
+
+	// 10.0.0.1:1234 -> 192.168.1.2:80
+	tcpPacket = serialize(
+		&layers.Ethernet{SrcMAC: macA, DstMAC: macB, EthernetType: layers.EthernetTypeIPv4},
+		&layers.IPv4{Version: 4, IHL: 5, TTL: 64, Protocol: layers.IPProtocolTCP, SrcIP: net.IP{10, 0, 0, 1}, DstIP: net.IP{192, 168, 1, 2}},
+		&layers.TCP{SrcPort: 1234, DstPort: 80, DataOffset: 5},
+		gopacket.Payload([]byte{0xC0, 0xFF, 0xEE}))
+
+	// [2001:db8::1]:5353 -> [2001:db8::2]:53
+	udpPacket = serialize(
+		&layers.Ethernet{SrcMAC: macB, DstMAC: macA, EthernetType: layers.EthernetTypeIPv6},
+		&layers.IPv6{Version: 6, HopLimit: 64, NextHeader: layers.IPProtocolUDP, SrcIP: net.ParseIP("2001:db8::1"), DstIP: net.ParseIP("2001:db8::2")},
+		&layers.UDP{SrcPort: 5353, DstPort: 53})
+
+	// ARP within VLAN 100
+	arpPacket = serialize(
+		&layers.Ethernet{SrcMAC: macA, DstMAC: layers.EthernetBroadcast, EthernetType: layers.EthernetTypeDot1Q},
+		&layers.Dot1Q{VLANIdentifier: 100, Type: layers.EthernetTypeARP},
+		&layers.ARP{AddrType: layers.LinkTypeEthernet, Protocol: layers.EthernetTypeIPv4, HwAddressSize: 6, ProtAddressSize: 4, Operation: layers.ARPRequest,
+			SourceHwAddress: macA, SourceProtAddress: []byte{10, 0, 0, 1}, DstHwAddress: make([]byte, 6), DstProtAddress: []byte{10, 0, 0, 2}})
+)
+
+func TestGetCaptureFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		magic  []byte
+		format string
+	}{
+		{name: "Pcapng", magic: []byte{0x0A, 0x0D, 0x0D, 0x0A, 0x1C, 0x00, 0x00, 0x00}, format: "pcapng"},
+		{name: "Pcap", magic: []byte{0xD4, 0xC3, 0xB2, 0xA1, 0x02, 0x00, 0x04, 0x00}, format: "pcap"},
+		{name: "Pcap nanoseconds", magic: []byte{0xA1, 0xB2, 0x3C, 0x4D}, format: "pcap"},
+		{name: "Too short", magic: []byte{0x0A, 0x0D}},
+		{name: "Svg", magic: []byte("<svg")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if format := getCaptureFormat(tc.magic); format != tc.format {
+				t.Fatalf("Expected: %v \t Got: %v", tc.format, format)
+			}
+		})
+	}
+}
+
+func TestInitOfflineSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestInitOfflineSource")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	timestamp := time.Unix(1257894000, 0)
+	ci := gopacket.CaptureInfo{Timestamp: timestamp, CaptureLength: len(tcpPacket), Length: len(tcpPacket)}
+
+	var pcap bytes.Buffer
+	w := pcapgo.NewWriter(&pcap)
+	if err := w.WriteFileHeader(65536, layers.LinkTypeEthernet); err != nil {
+		t.Fatalf("Could not create pcap: %v", err)
+	}
+	if err := w.WritePacket(ci, tcpPacket); err != nil {
+		t.Fatalf("Could not write packet: %v", err)
+	}
+
+	var raw bytes.Buffer
+	w = pcapgo.NewWriter(&raw)
+	if err := w.WriteFileHeader(65536, layers.LinkTypeRaw); err != nil {
+		t.Fatalf("Could not create pcap: %v", err)
+	}
+	if err := w.WritePacket(gopacket.CaptureInfo{Timestamp: timestamp, CaptureLength: len(tcpPacket) - 14, Length: len(tcpPacket) - 14}, tcpPacket[14:]); err != nil {
+		t.Fatalf("Could not write packet: %v", err)
+	}
+
+	var pcapng bytes.Buffer
+	ng, err := pcapgo.NewNgWriter(&pcapng, layers.LinkTypeEthernet)
+	if err != nil {
+		t.Fatalf("Could not create pcapng: %v", err)
+	}
+	if err := ng.WritePacket(ci, tcpPacket); err != nil {
+		t.Fatalf("Could not write packet: %v", err)
+	}
+	if err := ng.Flush(); err != nil {
+		t.Fatalf("Could not flush pcapng: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		content  []byte
+		filter   string
+		data     []byte
+		linkType layers.LinkType
+		err      string
+	}{
+		{name: "Pcap", content: pcap.Bytes(), data: tcpPacket, linkType: layers.LinkTypeEthernet},
+		{name: "Pcapng", content: pcapng.Bytes(), data: tcpPacket, linkType: layers.LinkTypeEthernet},
+		{name: "Matching filter", content: pcapng.Bytes(), filter: "tcp port 80", data: tcpPacket, linkType: layers.LinkTypeEthernet},
+		{name: "Raw IP", content: raw.Bytes(), filter: "tcp port 80", data: tcpPacket[14:], linkType: layers.LinkTypeRaw},
+		{name: "Filtered packet", content: pcap.Bytes(), filter: "udp", linkType: layers.LinkTypeEthernet},
+		{name: "Invalid filter", content: pcap.Bytes(), filter: "noFilter", err: "Invalid Filter: noFilter"},
+		{name: "Broken pcapng", content: pcapngMagic, err: "could not read"},
+		{name: "Unknown file format", content: []byte(notSvg), err: "unknown file format"},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filename := fmt.Sprintf("%s/%d", dir, i)
+			if err := ioutil.WriteFile(filename, tc.content, 0644); err != nil {
+				t.Fatalf("Could not write file: %v", err)
+			}
+			f, err := os.Open(filename)
+			if err != nil {
+				t.Fatalf("Could not open file: %v", err)
+			}
+			src, err := initOfflineSource(f, filename, tc.filter, 0)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			defer src.Close()
+			if linkType := getSourceLinkType(src); linkType != tc.linkType {
+				t.Fatalf("Expected: %v \t Got: %v", tc.linkType, linkType)
+			}
+			pkt, err := src.Read(context.Background(), 1500)
+			if tc.data == nil {
+				if err != io.EOF {
+					t.Fatalf("Expected: %v \t Got: %v", io.EOF, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Could not read packet: %v", err)
+			}
+			if !bytes.Equal(pkt.Data, tc.data) || !pkt.Timestamp.Equal(timestamp) {
+				t.Fatalf("Unexpected packet: %v", pkt)
+			}
+		})
+	}
+}
+
+func TestInitStreamSource(t *testing.T) {
+	var pcap bytes.Buffer
+	w := pcapgo.NewWriter(&pcap)
+	if err := w.WriteFileHeader(65536, layers.LinkTypeEthernet); err != nil {
+		t.Fatalf("Could not create pcap: %v", err)
+	}
+	if err := w.WritePacket(gopacket.CaptureInfo{Timestamp: time.Unix(1257894000, 0), CaptureLength: len(tcpPacket), Length: len(tcpPacket)}, tcpPacket); err != nil {
+		t.Fatalf("Could not write packet: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		filter  string
+		pcap    bool
+		framing framingOpts
+		capture bool // Stream is expected to be read as capture
+		data    []byte
+		err     string
+	}{
+		{name: "Pcap", content: pcap.Bytes(), capture: true, data: tcpPacket},
+		{name: "Pcap with filter", content: pcap.Bytes(), filter: "tcp", capture: true, data: tcpPacket},
+		{name: "Raw bytes", content: []byte(notSvg), data: []byte(notSvg)},
+		{name: "Pcap with framing", content: pcap.Bytes(), framing: framingOpts{framing: "fixed:4", recordLen: 4}, data: pcap.Bytes()[:4]},
+		{name: "Raw bytes with pcap", content: []byte(notSvg), pcap: true, err: "could not read stdin: unknown file format"},
+		{name: "Raw bytes with filter", content: []byte(notSvg), filter: "tcp", err: "unknown file format"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := initStreamSource(context.Background(), ioutil.NopCloser(bytes.NewReader(tc.content)), "stdin", tc.filter, tc.pcap, 0, tc.framing)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			defer src.Close()
+			if _, ok := src.(pcapInput); ok != tc.capture {
+				t.Fatalf("Expected capture: %v \t Got: %v", tc.capture, ok)
+			}
+			pkt, err := src.Read(context.Background(), 1500)
+			if err != nil {
+				t.Fatalf("Could not read packet: %v", err)
+			}
+			if !bytes.Equal(pkt.Data, tc.data) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.data, pkt.Data)
+			}
+		})
+	}
+}
+
+func TestStreamSourceCancel(t *testing.T) {
+	var pcap bytes.Buffer
+	w := pcapgo.NewWriter(&pcap)
+	if err := w.WriteFileHeader(65536, layers.LinkTypeEthernet); err != nil {
+		t.Fatalf("Could not create pcap: %v", err)
+	}
+	if err := w.WritePacket(gopacket.CaptureInfo{Timestamp: time.Unix(1257894000, 0), CaptureLength: len(tcpPacket), Length: len(tcpPacket)}, tcpPacket); err != nil {
+		t.Fatalf("Could not write packet: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "Pcap", content: pcap.Bytes()},
+		{name: "Raw bytes", content: []byte(notSvg)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// The writer keeps the stream open without sending more data
+			r, w := io.Pipe()
+			defer w.Close()
+			go w.Write(tc.content)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			src, err := initStreamSource(ctx, r, "stdin", "", false, 0, framingOpts{})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			defer src.Close()
+
+			done := make(chan error, 1)
+			go func() {
+				for {
+					if _, err := src.Read(ctx, 1500); err != nil {
+						done <- err
+						return
+					}
+				}
+			}()
+			cancel()
+
+			select {
+			case err := <-done:
+				if err != context.Canceled {
+					t.Fatalf("Expected: %v \t Got: %v", context.Canceled, err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("Reading from the stream ignored the cancellation")
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+func TestInitSourceFifo(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestInitSourceFifo")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	var pcap bytes.Buffer
+	w := pcapgo.NewWriter(&pcap)
+	if err := w.WriteFileHeader(65536, layers.LinkTypeEthernet); err != nil {
+		t.Fatalf("Could not create pcap: %v", err)
+	}
+	if err := w.WritePacket(gopacket.CaptureInfo{Timestamp: time.Unix(1257894000, 0), CaptureLength: len(tcpPacket), Length: len(tcpPacket)}, tcpPacket); err != nil {
+		t.Fatalf("Could not write packet: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+		capture bool // Stream is expected to be read as capture
+	}{
+		{name: "Pcap", content: pcap.Bytes(), capture: true},
+		{name: "Raw bytes", content: tcpPacket},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			fifo := fmt.Sprintf("%s/%d", dir, i)
+			if err := syscall.Mkfifo(fifo, 0600); err != nil {
+				t.Fatalf("Could not create fifo: %v", err)
+			}
+			go func() {
+				ioutil.WriteFile(fifo, tc.content, 0600)
+			}()
+
+			src, err := initSource(context.Background(), fifo, "", false, "link", captureOpts{snaplen: 1500, timeout: time.Second}, framingOpts{})
+			if err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			defer src.Close()
+			if _, ok := src.(pcapInput); ok != tc.capture {
+				t.Fatalf("Expected capture: %v \t Got: %v", tc.capture, ok)
+			}
+			pkt, err := src.Read(context.Background(), 1500)
+			if err != nil {
+				t.Fatalf("Could not read packet: %v", err)
+			}
+			if !bytes.Equal(pkt.Data, tcpPacket) {
+				t.Fatalf("Expected: %v \t Got: %v", tcpPacket, pkt.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package netviz
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// Block types of pcapng
+const (
+	ngSectionHeader       = 0x0A0D0D0A
+	ngInterfaceDescriptor = 0x00000001
+	ngEnhancedPacket      = 0x00000006
+)
+
+// Option codes of pcapng
+const (
+	ngOptEnd         = 0
+	ngOptComment     = 1
+	ngOptUserAppl    = 4 // Section header block
+	ngOptIfName      = 2 // Interface description block
+	ngOptIfDesc      = 3 // Interface description block
+	ngOptIfTsresol   = 9 // Interface description block
+	ngTsresolNanosec = 9
+)
+
+// packetWriter writes reconstructed packets into a capture file
+type packetWriter interface {
+	writeHeader(linkType layers.LinkType) error
+	writePacket(ci gopacket.CaptureInfo, payload []byte, comment string) error
+}
+
+// pcapWriter writes classic pcap files, which don't support comments
+type pcapWriter struct {
+	w *pcapgo.Writer
+}
+
+func (p pcapWriter) writeHeader(linkType layers.LinkType) error {
+	return p.w.WriteFileHeader(65536, linkType)
+}
+
+func (p pcapWriter) writePacket(ci gopacket.CaptureInfo, payload []byte, comment string) error {
+	return p.w.WritePacket(ci, payload)
+}
+
+// pcapngWriter writes pcapng files with a single interface
+type pcapngWriter struct {
+	w           io.Writer
+	name        string // Name of the interface
+	description string // Description of the interface
+}
+
+// ngOption returns an option of a pcapng block padded to 32 bits
+func ngOption(code uint16, value []byte) []byte {
+	var opt bytes.Buffer
+
+	binary.Write(&opt, binary.LittleEndian, code)
+	binary.Write(&opt, binary.LittleEndian, uint16(len(value)))
+	opt.Write(value)
+	opt.Write(make([]byte, (4-len(value)%4)%4))
+	return opt.Bytes()
+}
+
+// writeBlock writes a pcapng block of the given type with body and options
+func (p pcapngWriter) writeBlock(blockType uint32, body []byte, options ...[]byte) error {
+	var block bytes.Buffer
+
+	content := append([]byte{}, body...)
+	if len(options) > 0 {
+		for _, opt := range options {
+			content = append(content, opt...)
+		}
+		content = append(content, ngOption(ngOptEnd, nil)...)
+	}
+	length := uint32(12 + len(content))
+
+	binary.Write(&block, binary.LittleEndian, blockType)
+	binary.Write(&block, binary.LittleEndian, length)
+	block.Write(content)
+	binary.Write(&block, binary.LittleEndian, length)
+
+	_, err := p.w.Write(block.Bytes())
+	return err
+}
+
+func (p pcapngWriter) writeHeader(linkType layers.LinkType) error {
+	var shb, idb bytes.Buffer
+
+	binary.Write(&shb, binary.LittleEndian, uint32(0x1A2B3C4D))
+	binary.Write(&shb, binary.LittleEndian, uint16(1))
+	binary.Write(&shb, binary.LittleEndian, uint16(0))
+	binary.Write(&shb, binary.LittleEndian, int64(-1))
+	if err := p.writeBlock(ngSectionHeader, shb.Bytes(), ngOption(ngOptUserAppl, []byte("goNetViz "+Version))); err != nil {
+		return err
+	}
+
+	binary.Write(&idb, binary.LittleEndian, uint16(linkType))
+	binary.Write(&idb, binary.LittleEndian, uint16(0))
+	binary.Write(&idb, binary.LittleEndian, uint32(65536))
+	return p.writeBlock(ngInterfaceDescriptor, idb.Bytes(),
+		ngOption(ngOptIfName, []byte(p.name)),
+		ngOption(ngOptIfDesc, []byte(p.description)),
+		ngOption(ngOptIfTsresol, []byte{ngTsresolNanosec}))
+}
+
+func (p pcapngWriter) writePacket(ci gopacket.CaptureInfo, payload []byte, comment string) error {
+	var epb bytes.Buffer
+
+	ts := uint64(ci.Timestamp.UnixNano())
+	binary.Write(&epb, binary.LittleEndian, uint32(0))
+	binary.Write(&epb, binary.LittleEndian, uint32(ts>>32))
+	binary.Write(&epb, binary.LittleEndian, uint32(ts))
+	binary.Write(&epb, binary.LittleEndian, uint32(ci.CaptureLength))
+	binary.Write(&epb, binary.LittleEndian, uint32(ci.Length))
+	epb.Write(payload)
+	epb.Write(make([]byte, (4-len(payload)%4)%4))
+
+	if len(comment) == 0 {
+		return p.writeBlock(ngEnhancedPacket, epb.Bytes())
+	}
+	return p.writeBlock(ngEnhancedPacket, epb.Bytes(), ngOption(ngOptComment, []byte(comment)))
+}
@@ -0,0 +1,80 @@
+package netviz
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+)
+
+func TestCreatePcapng(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestCreatePcapng")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	tests := []struct {
+		name     string
+		payload  []byte
+		layer    int
+		linkType layers.LinkType
+	}{
+		{name: "Simple", payload: []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05}, linkType: layers.LinkTypeEthernet},
+		{name: "Network layer", payload: []byte{0x45, 0x00, 0x00, 0x14}, layer: 1, linkType: layers.LinkTypeRaw},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := configs{bpP: 1, scale: 1, xlimit: 1500, input: "/tmp/image-1.svg", prefix: fmt.Sprintf("%s/%d", dir, i), format: "pcapng", logicOp: logic}
+			packets := []data{{toa: 1257894000000000123, len: len(tc.payload), olen: 1500, layer: tc.layer, linkType: tc.linkType, row: 7, payload: tc.payload}}
+			if err := createPcap(packets, cfg); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+			raw, err := ioutil.ReadFile(fmt.Sprintf("%s.pcapng", cfg.prefix))
+			if err != nil {
+				t.Fatalf("Could not read pcapng: %v", err)
+			}
+			if !bytes.Contains(raw, []byte("image-1.svg row 7")) {
+				t.Fatalf("Missing comment of packet")
+			}
+			r, err := pcapgo.NewNgReader(bytes.NewReader(raw), pcapgo.DefaultNgReaderOptions)
+			if err != nil {
+				t.Fatalf("Could not read pcapng: %v", err)
+			}
+			if r.LinkType() != tc.linkType {
+				t.Fatalf("Expected: %v \t Got: %v", tc.linkType, r.LinkType())
+			}
+			payload, ci, err := r.ReadPacketData()
+			if err != nil {
+				t.Fatalf("Could not read packet: %v", err)
+			}
+			if !bytes.Equal(payload, tc.payload) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.payload, payload)
+			}
+			if ci.Timestamp.UnixNano() != 1257894000000000123 || ci.CaptureLength != len(tc.payload) || ci.Length != 1500 {
+				t.Fatalf("Unexpected capture information: %v", ci)
+			}
+			intf, err := r.Interface(0)
+			if err != nil {
+				t.Fatalf("Could not get interface: %v", err)
+			}
+			if intf.Name != "goNetViz" || intf.Description != "Reconstructed from image-1.svg" {
+				t.Fatalf("Unexpected interface: %v", intf)
+			}
+			if app := r.SectionInfo().Application; app != "goNetViz "+Version {
+				t.Fatalf("Expected: goNetViz %s \t Got: %s", Version, app)
+			}
+		})
+	}
+}
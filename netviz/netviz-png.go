@@ -0,0 +1,258 @@
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"time"
+
+	"github.com/google/gopacket/layers"
+)
+
+// pngSignatureLen is the length of the signature and the IHDR chunk of a png
+const pngSignatureLen = 8 + 4 + 4 + 13 + 4
+
+// pngMagic is the signature every png starts with
+var pngMagic = []byte{0x89, 0x50, 0x4E, 0x47, 0x0D, 0x0A, 0x1A, 0x0A}
+
+// pngText represents a tEXt chunk of a png
+type pngText struct {
+	key   string
+	value string
+}
+
+// createChunk returns a png chunk of the given type with content
+func createChunk(chunkType string, content []byte) []byte {
+	var chunk bytes.Buffer
+	raw := append([]byte(chunkType), content...)
+
+	binary.Write(&chunk, binary.BigEndian, uint32(len(content)))
+	chunk.Write(raw)
+	binary.Write(&chunk, binary.BigEndian, crc32.ChecksumIEEE(raw))
+
+	return chunk.Bytes()
+}
+
+func createTextChunk(key, value string) []byte {
+	content := append([]byte(key), 0x00)
+	content = append(content, []byte(value)...)
+
+	return createChunk("tEXt", content)
+}
+
+// drawRows returns an image of the given size with the pixels of rows
+func drawRows(width, height int, rows []Row, scale int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for _, r := range rows {
+		for _, p := range r.Pixels {
+			c := color.RGBA{R: p.R, G: p.G, B: p.B, A: 0xFF}
+			for y := 0; y < scale; y++ {
+				for x := 0; x < scale; x++ {
+					img.SetRGBA(p.X*scale+x, p.Y*scale+y, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func createPNG(filename string, width, height int, rows []Row, cfg configs) error {
+	var buf bytes.Buffer
+
+	if len(rows) == 0 {
+		return fmt.Errorf("no content to write")
+	}
+
+	meta, err := newMetadata(cfg, rows).encode()
+	if err != nil {
+		return err
+	}
+
+	img := drawRows(width, height, rows, int(cfg.scale))
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("could not encode image: %s", err.Error())
+	}
+	encoded := buf.Bytes()
+
+	f, err := createFile(filename, cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(encoded[:pngSignatureLen]); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write header: %s", err.Error())
+	}
+
+	information := []pngText{
+		{key: "goNetViz", value: Version},
+		{key: "Scale", value: fmt.Sprintf("%d", cfg.scale)},
+		{key: "BitsPerPixel", value: fmt.Sprintf("%d", cfg.bpP)},
+		{key: "DTG", value: time.Now().UTC().String()},
+		{key: "Source", value: cfg.input},
+		{key: "Filter", value: cfg.filter},
+		{key: "LogicGate", value: cfg.logicOp.name},
+		{key: "LogicValue", value: fmt.Sprintf("0x%X", cfg.logicOp.value)},
+		{key: "LayerTint", value: strconv.FormatBool((cfg.flags & layerTint) == layerTint)},
+		{key: "Layer", value: cfg.layer},
+		{key: "Metadata", value: meta},
+	}
+	for _, text := range information {
+		if _, err := f.Write(createTextChunk(text.key, text.value)); err != nil {
+			f.Close()
+			return fmt.Errorf("could not write additional information: %s", err.Error())
+		}
+	}
+
+	if _, err := f.Write(encoded[pngSignatureLen:]); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write content: %s", err.Error())
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not close file %s: %s", filename, err.Error())
+	}
+	return nil
+}
+
+func getTextChunks(raw []byte) (map[string]string, error) {
+	text := make(map[string]string)
+
+	if !bytes.HasPrefix(raw, pngMagic) {
+		return text, fmt.Errorf("not a png")
+	}
+
+	for pos := len(pngMagic); pos+8 <= len(raw); {
+		length := int(binary.BigEndian.Uint32(raw[pos : pos+4]))
+		chunkType := string(raw[pos+4 : pos+8])
+		if pos+12+length > len(raw) {
+			return text, fmt.Errorf("chunk %s is truncated", chunkType)
+		}
+		if chunkType == "tEXt" {
+			content := raw[pos+8 : pos+8+length]
+			if i := bytes.IndexByte(content, 0x00); i > 0 {
+				text[string(content[:i])] = string(content[i+1:])
+			}
+		}
+		if chunkType == "IEND" {
+			break
+		}
+		pos += 12 + length
+	}
+	return text, nil
+}
+
+func checkPNGHeader(text map[string]string) (reconstructOptions, error) {
+	options := reconstructOptions{LinkType: layers.LinkTypeEthernet}
+	var value int64
+	var err error
+
+	version, ok := text["goNetViz"]
+	if !ok {
+		return options, fmt.Errorf("no goNetViz information found")
+	}
+
+	switch version {
+	case "0.0.5", "0.0.4":
+		options.LogicGate = text["LogicGate"]
+		if value, err = strconv.ParseInt(text["LogicValue"], 0, 64); err != nil {
+			return options, fmt.Errorf("could not convert LogicValue: %s", err.Error())
+		}
+		options.LogicValue = int(value)
+		options.Dtg = text["DTG"]
+		options.Source = text["Source"]
+		options.Filter = text["Filter"]
+		options.LayerTint = text["LayerTint"]
+		options.Layer = text["Layer"]
+	default:
+		return options, fmt.Errorf("unrecognized version: %s", version)
+	}
+
+	if options.Scale, err = strconv.Atoi(text["Scale"]); err != nil {
+		return options, fmt.Errorf("could not convert Scale: %s", err.Error())
+	}
+	if options.BpP, err = strconv.Atoi(text["BitsPerPixel"]); err != nil {
+		return options, fmt.Errorf("could not convert BitsPerPixel: %s", err.Error())
+	}
+
+	return options, nil
+}
+
+func extractPNGInformation(ctx context.Context, ch chan data, input io.Reader) error {
+	raw, err := ioutil.ReadAll(input)
+	if err != nil {
+		return fmt.Errorf("could not read png: %s", err.Error())
+	}
+
+	text, err := getTextChunks(raw)
+	if err != nil {
+		return err
+	}
+
+	var opt reconstructOptions
+	// Images without metadata carry no timestamps and lengths of their packets
+	packets := make(map[int]data)
+	if meta, ok := text["Metadata"]; ok {
+		if opt, err = getMetadataOptions(meta); err == nil {
+			packets, err = getMetadataPackets(meta)
+		}
+	} else {
+		opt, err = checkPNGHeader(text)
+	}
+	if err != nil {
+		return err
+	}
+	if opt.Scale < 1 {
+		return fmt.Errorf("scale factor has to be at least 1")
+	}
+
+	if opt.LayerTint == "true" {
+		return fmt.Errorf("images with tinted layers can't be reversed")
+	}
+
+	layer, err := getReverseLayer(opt.Layer)
+	if err != nil {
+		return err
+	}
+
+	undo, err := getReverseOp(opt.LogicGate, opt.LogicValue)
+	if err != nil {
+		return err
+	}
+
+	img, err := png.Decode(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("could not decode png: %s", err.Error())
+	}
+	bounds := img.Bounds()
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += opt.Scale {
+		var packet []int
+		for x := bounds.Min.X; x < bounds.Max.X; x += opt.Scale {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a == 0 {
+				break
+			}
+			packet = append(packet, int(r>>8), int(g>>8), int(b>>8))
+		}
+		if len(packet) == 0 {
+			continue
+		}
+		info := packets[y/opt.Scale]
+		info.layer = layer
+		info.linkType = opt.LinkType
+		info.row = y / opt.Scale
+		if err := createPacket(ctx, ch, packet, opt.BpP, undo, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sync"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+)
+
+func TestCreateTextChunk(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		key   string
+		value string
+		chunk []byte
+	}{
+		{name: "Empty value", key: "Filter", value: "", chunk: []byte{0x00, 0x00, 0x00, 0x07, 0x74, 0x45, 0x58, 0x74, 0x46, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x00, 0x5f, 0xd5, 0x81, 0x94}},
+		{name: "Version", key: "goNetViz", value: "0.0.4", chunk: []byte{0x00, 0x00, 0x00, 0x0e, 0x74, 0x45, 0x58, 0x74, 0x67, 0x6f, 0x4e, 0x65, 0x74, 0x56, 0x69, 0x7a, 0x00, 0x30, 0x2e, 0x30, 0x2e, 0x34, 0x95, 0x59, 0xc8, 0xe0}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			chunk := createTextChunk(tc.key, tc.value)
+			if !bytes.Equal(chunk, tc.chunk) {
+				t.Fatalf("Expected: %#v \t Got: %#v", tc.chunk, chunk)
+			}
+		})
+	}
+}
+
+func TestCreatePNG(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestCreatePNG")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		width    int
+		height   int
+		rows     []Row
+		cfg      configs
+		err      string
+	}{
+		{name: "No Data", filename: fmt.Sprintf("%s/noData.png", dir), width: 1, height: 1, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "png", logicOp: logic}, err: "no content to write"},
+		{name: "Just directory name", filename: dir, width: 1, height: 1, rows: []Row{{Pixels: []Pixel{{X: 0, Y: 0, R: 0xCA, G: 0xFE, B: 0xC0}}}}, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "png", logicOp: logic}, err: "could not open file"},
+		{name: "Single pixel", filename: fmt.Sprintf("%s/single.png", dir), width: 1, height: 1, rows: []Row{{Arrival: 42, CapLen: 3, Len: 64, Pixels: []Pixel{{X: 0, Y: 0, R: 0xCA, G: 0xFE, B: 0xC0}}}}, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "png", logicOp: logic}},
+		{name: "Scaled", filename: fmt.Sprintf("%s/scaled.png", dir), width: 6, height: 3, rows: []Row{{Pixels: []Pixel{{X: 0, Y: 0, R: 0xCA, G: 0xFE, B: 0xC0}, {X: 1, Y: 0, R: 0xFF, G: 0xEE}}}}, cfg: configs{bpP: 24, flags: solder, scale: 3, xlimit: 1500, input: "input", format: "png", logicOp: logic}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := createPNG(tc.filename, tc.width, tc.height, tc.rows, tc.cfg)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			f, err := os.Open(tc.filename)
+			if err != nil {
+				t.Fatalf("Could not open image: %v", err)
+			}
+			defer f.Close()
+			img, err := png.Decode(f)
+			if err != nil {
+				t.Fatalf("Could not decode image: %v", err)
+			}
+			if img.Bounds().Dx() != tc.width || img.Bounds().Dy() != tc.height {
+				t.Fatalf("Expected: %dx%d \t Got: %dx%d", tc.width, tc.height, img.Bounds().Dx(), img.Bounds().Dy())
+			}
+			scale := int(tc.cfg.scale)
+			for _, row := range tc.rows {
+				for _, p := range row.Pixels {
+					r, g, b, _ := img.At(p.X*scale+scale-1, p.Y*scale+scale-1).RGBA()
+					if uint8(r>>8) != p.R || uint8(g>>8) != p.G || uint8(b>>8) != p.B {
+						t.Fatalf("Expected: r%dg%db%d\t Got: r%dg%db%d", p.R, p.G, p.B, uint8(r>>8), uint8(g>>8), uint8(b>>8))
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestExtractPNGInformation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestExtractPNGInformation")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	plainPNG, err := os.Create(fmt.Sprintf("%s/plain.png", dir))
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	if err := png.Encode(plainPNG, image.NewRGBA(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatalf("Could not encode image: %v", err)
+	}
+	if err := plainPNG.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		rows   []Row
+		width  int
+		height int
+		logic  logicOp
+		scale  uint
+		bpP    uint
+		recv   []byte
+		err    string
+	}{
+		{name: "24 BitsPerPixel", rows: []Row{{Pixels: []Pixel{{X: 0, Y: 0, R: 1, G: 2, B: 3}, {X: 1, Y: 0, R: 4, G: 5, B: 6}}}, {Pixels: []Pixel{{X: 0, Y: 1, R: 7, G: 8, B: 9}}}}, width: 2, height: 2, logic: logicOp{name: "none"}, scale: 1, bpP: 24, recv: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{name: "Scaled", rows: []Row{{Pixels: []Pixel{{X: 0, Y: 0, R: 1, G: 2, B: 3}, {X: 1, Y: 0, R: 4, G: 5, B: 6}}}, {Pixels: []Pixel{{X: 0, Y: 1, R: 7, G: 8, B: 9}}}}, width: 6, height: 6, logic: logicOp{name: "none"}, scale: 3, bpP: 24, recv: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{name: "Empty rows", rows: []Row{{Pixels: []Pixel{{X: 0, Y: 0, R: 1, G: 2, B: 3}}}, {Pixels: []Pixel{{X: 0, Y: 3, R: 0, G: 0, B: 0}}}}, width: 1, height: 4, logic: logicOp{name: "none"}, scale: 1, bpP: 24, recv: []byte{1, 2, 3, 0, 0, 0}},
+		{name: "1 BitPerPixel", rows: []Row{{Pixels: []Pixel{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}, {X: 4, Y: 0}, {X: 5, Y: 0}, {X: 6, Y: 0}, {X: 7, Y: 0, R: 255, G: 255, B: 255}}}}, width: 8, height: 1, logic: logicOp{name: "none"}, scale: 1, bpP: 1, recv: []byte{1}},
+		{name: "Xor", rows: []Row{{Pixels: []Pixel{{X: 0, Y: 0, R: 0xF0, G: 0x0F, B: 0xFF}}}}, width: 1, height: 1, logic: logicOp{name: "xor", value: 0xFF}, scale: 1, bpP: 24, recv: []byte{0x0F, 0xF0, 0x00}},
+		{name: "Truncated", rows: []Row{{Arrival: 1, CapLen: 2, Len: 1500, Pixels: []Pixel{{X: 0, Y: 0, R: 1, G: 2, B: 0}}}, {Arrival: 3, CapLen: 4, Len: 4, Pixels: []Pixel{{X: 0, Y: 1, R: 3, G: 4, B: 5}, {X: 1, Y: 1, R: 6}}}}, width: 2, height: 2, logic: logicOp{name: "none"}, scale: 1, bpP: 24, recv: []byte{1, 2, 3, 4, 5, 6}},
+		{name: "Nand", rows: []Row{{Pixels: []Pixel{{X: 0, Y: 0, R: 0xF0, G: 0x0F, B: 0xFF}}}}, width: 1, height: 1, logic: logicOp{name: "nand", value: 0xFF}, scale: 1, bpP: 24, err: "logic gate nand is not reversible"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filename := fmt.Sprintf("%s/%s.png", dir, tc.name)
+			cfg := configs{bpP: tc.bpP, flags: solder, scale: tc.scale, xlimit: 1500, input: "input", format: "png", logicOp: tc.logic}
+			if err := createPNG(filename, tc.width, tc.height, tc.rows, cfg); err != nil {
+				t.Fatalf("Could not create image: %v", err)
+			}
+			cfg.input = filename
+			g, _ := errgroup.WithContext(context.Background())
+			var wg sync.WaitGroup
+			wg.Add(1)
+			ch := make(chan data)
+			var recv []byte
+			go func() {
+				defer wg.Done()
+				for i, ok := <-ch; ok; i, ok = <-ch {
+					recv = append(recv, i.payload...)
+				}
+			}()
+			err := extractInformation(context.Background(), g, ch, cfg)
+			wg.Wait()
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			if !bytes.Equal(recv, tc.recv) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.recv, recv)
+			}
+		})
+	}
+
+	t.Run("Without information", func(t *testing.T) {
+		g, _ := errgroup.WithContext(context.Background())
+		ch := make(chan data)
+		go func() {
+			for _, ok := <-ch; ok; _, ok = <-ch {
+			}
+		}()
+		cfg := configs{bpP: 24, flags: reverse, scale: 1, xlimit: 1500, input: plainPNG.Name()}
+		if err := extractInformation(context.Background(), g, ch, cfg); err == nil {
+			t.Fatalf("Expected error, got none")
+		}
+	})
+}
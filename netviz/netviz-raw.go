@@ -0,0 +1,205 @@
+package netviz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// framingOpts represents how raw input is split into records
+type framingOpts struct {
+	framing   string           // Description of the framing as given by -framing
+	recordLen int              // Length of each record with fixed framing
+	delimiter []byte           // Separator of the records with delim framing
+	prefixLen int              // Size of the length prefix of each record with len framing
+	byteOrder binary.ByteOrder // Byte order of the length prefix
+}
+
+// getFraming returns the framing described by spec. Supported are
+// fixed:<bytes>, delim:<separator> and len:<1|2be|2le|4be|4le>.
+// The separator is either hex with a leading 0x or an escaped string like \n.
+// An empty spec splits the input into chunks of -limit bytes.
+func getFraming(spec string) (framingOpts, error) {
+	opts := framingOpts{framing: spec}
+	if len(spec) == 0 {
+		return opts, nil
+	}
+
+	kind, arg := spec, ""
+	if i := strings.Index(spec, ":"); i >= 0 {
+		kind, arg = spec[:i], spec[i+1:]
+	}
+
+	switch strings.ToLower(kind) {
+	case "fixed":
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			return opts, fmt.Errorf("-framing %s needs a positive record length", spec)
+		}
+		opts.recordLen = n
+	case "delim":
+		delimiter, err := getDelimiter(arg)
+		if err != nil || len(delimiter) == 0 {
+			return opts, fmt.Errorf("-framing %s needs a valid separator", spec)
+		}
+		opts.delimiter = delimiter
+	case "len":
+		switch strings.ToLower(arg) {
+		case "1":
+			opts.prefixLen, opts.byteOrder = 1, binary.BigEndian
+		case "2be":
+			opts.prefixLen, opts.byteOrder = 2, binary.BigEndian
+		case "2le":
+			opts.prefixLen, opts.byteOrder = 2, binary.LittleEndian
+		case "4be":
+			opts.prefixLen, opts.byteOrder = 4, binary.BigEndian
+		case "4le":
+			opts.prefixLen, opts.byteOrder = 4, binary.LittleEndian
+		default:
+			return opts, fmt.Errorf("-framing %s needs a length prefix of 1, 2be, 2le, 4be or 4le", spec)
+		}
+	default:
+		return opts, fmt.Errorf("-framing %s is not supported", spec)
+	}
+	return opts, nil
+}
+
+// getDelimiter returns the bytes of the separator arg
+func getDelimiter(arg string) ([]byte, error) {
+	if strings.HasPrefix(strings.ToLower(arg), "0x") {
+		return hex.DecodeString(arg[2:])
+	}
+	unquoted, err := strconv.Unquote("\"" + arg + "\"")
+	return []byte(unquoted), err
+}
+
+// regularFile reads raw bytes and splits them into records by its framing
+type regularFile struct {
+	file    io.ReadCloser
+	reader  *bufio.Reader
+	framing framingOpts
+}
+
+func newRegularFile(r io.ReadCloser, framing framingOpts) regularFile {
+	return regularFile{file: r, reader: bufio.NewReader(r), framing: framing}
+}
+
+func (f regularFile) Read(ctx context.Context, limit uint) (Packet, error) {
+	if err := ctx.Err(); err != nil {
+		return Packet{}, err
+	}
+
+	switch {
+	case f.framing.recordLen > 0:
+		return f.readFixed()
+	case len(f.framing.delimiter) > 0:
+		return f.readDelimited(limit)
+	case f.framing.prefixLen > 0:
+		return f.readPrefixed(limit)
+	}
+
+	// Only the last chunk of the input can be shorter than limit
+	buf := make([]byte, int(limit))
+	n, err := io.ReadFull(f.reader, buf)
+	if n == 0 {
+		return Packet{}, err
+	}
+	return Packet{Length: n, Data: buf[:n]}, nil
+}
+
+// readFixed returns the next record of a fixed length. Only the last
+// record of the input can be shorter.
+func (f regularFile) readFixed() (Packet, error) {
+	buf := make([]byte, f.framing.recordLen)
+	n, err := io.ReadFull(f.reader, buf)
+	if n == 0 {
+		return Packet{}, err
+	}
+	return Packet{Length: n, Data: buf[:n]}, nil
+}
+
+// readDelimited returns the bytes up to the next separator. At most
+// limit bytes of a record are kept.
+func (f regularFile) readDelimited(limit uint) (Packet, error) {
+	var record []byte
+	var total int
+	delimiter := f.framing.delimiter
+	window := make([]byte, 0, len(delimiter))
+
+	for {
+		b, err := f.reader.ReadByte()
+		if err == io.EOF && total > 0 {
+			break
+		} else if err != nil {
+			return Packet{}, err
+		}
+		total++
+		if len(record) < int(limit)+len(delimiter) {
+			record = append(record, b)
+		}
+		if len(window) == len(delimiter) {
+			copy(window, window[1:])
+			window = window[:len(window)-1]
+		}
+		window = append(window, b)
+		if bytes.Equal(window, delimiter) {
+			total -= len(delimiter)
+			break
+		}
+	}
+
+	if len(record) > total {
+		record = record[:total]
+	}
+	if len(record) > int(limit) {
+		record = record[:limit]
+	}
+	return Packet{Length: len(record), OrigLength: total, Data: record}, nil
+}
+
+// readPrefixed returns the next record, whose length is given by a prefix
+// in front of it. At most limit bytes of a record are kept.
+func (f regularFile) readPrefixed(limit uint) (Packet, error) {
+	var length uint64
+
+	prefix := make([]byte, f.framing.prefixLen)
+	if _, err := io.ReadFull(f.reader, prefix); err == io.ErrUnexpectedEOF {
+		return Packet{}, fmt.Errorf("could not read length prefix: %s", err.Error())
+	} else if err != nil {
+		return Packet{}, err
+	}
+	switch f.framing.prefixLen {
+	case 1:
+		length = uint64(prefix[0])
+	case 2:
+		length = uint64(f.framing.byteOrder.Uint16(prefix))
+	default:
+		length = uint64(f.framing.byteOrder.Uint32(prefix))
+	}
+
+	keep := length
+	if keep > uint64(limit) {
+		keep = uint64(limit)
+	}
+	buf := make([]byte, int(keep))
+	n, err := io.ReadFull(f.reader, buf)
+	if err == nil && length > keep {
+		_, err = io.CopyN(ioutil.Discard, f.reader, int64(length-keep))
+	}
+	// A truncated last record is kept with its announced length
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return Packet{}, err
+	}
+	return Packet{Length: n, OrigLength: int(length), Data: buf[:n]}, nil
+}
+
+func (f regularFile) Close() error {
+	return f.file.Close()
+}
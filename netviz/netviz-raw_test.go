@@ -0,0 +1,121 @@
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestGetFraming(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		framing framingOpts
+		err     string
+	}{
+		{name: "None", spec: ""},
+		{name: "Fixed", spec: "fixed:16", framing: framingOpts{framing: "fixed:16", recordLen: 16}},
+		{name: "Newline", spec: `delim:\n`, framing: framingOpts{framing: `delim:\n`, delimiter: []byte{'\n'}}},
+		{name: "Sync word", spec: "delim:0x1ACFFC1D", framing: framingOpts{framing: "delim:0x1ACFFC1D", delimiter: []byte{0x1A, 0xCF, 0xFC, 0x1D}}},
+		{name: "One byte prefix", spec: "len:1", framing: framingOpts{framing: "len:1", prefixLen: 1, byteOrder: binary.BigEndian}},
+		{name: "Little endian prefix", spec: "LEN:2le", framing: framingOpts{framing: "LEN:2le", prefixLen: 2, byteOrder: binary.LittleEndian}},
+		{name: "Big endian prefix", spec: "len:4be", framing: framingOpts{framing: "len:4be", prefixLen: 4, byteOrder: binary.BigEndian}},
+		{name: "Zero length", spec: "fixed:0", err: "-framing fixed:0 needs a positive record length"},
+		{name: "Missing separator", spec: "delim", err: "needs a valid separator"},
+		{name: "Invalid hex", spec: "delim:0xZZ", err: "needs a valid separator"},
+		{name: "Invalid prefix", spec: "len:3", err: "needs a length prefix of 1, 2be, 2le, 4be or 4le"},
+		{name: "Unknown", spec: "lines", err: "-framing lines is not supported"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			framing, err := getFraming(tc.spec)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			if !reflect.DeepEqual(framing, tc.framing) {
+				t.Fatalf("Expected: %+v \t Got: %+v", tc.framing, framing)
+			}
+		})
+	}
+}
+
+func TestRegularFileRead(t *testing.T) {
+	// Larger than the buffer of the reader, so chunks span several reads
+	large := bytes.Repeat([]byte("0123456789"), 920)
+
+	tests := []struct {
+		name    string
+		content []byte
+		framing string
+		limit   uint
+		packets []Packet
+		err     string
+	}{
+		{name: "Chunks", content: []byte("abcdefg"), limit: 4,
+			packets: []Packet{{Length: 4, Data: []byte("abcd")}, {Length: 3, Data: []byte("efg")}}},
+		{name: "Large chunks", content: large, limit: 1500,
+			packets: []Packet{{Length: 1500, Data: large[:1500]}, {Length: 1500, Data: large[1500:3000]}, {Length: 1500, Data: large[3000:4500]},
+				{Length: 1500, Data: large[4500:6000]}, {Length: 1500, Data: large[6000:7500]}, {Length: 1500, Data: large[7500:9000]}, {Length: 200, Data: large[9000:]}}},
+		{name: "Fixed", content: []byte("abcdefg"), framing: "fixed:3", limit: 1500,
+			packets: []Packet{{Length: 3, Data: []byte("abc")}, {Length: 3, Data: []byte("def")}, {Length: 1, Data: []byte("g")}}},
+		{name: "Newline", content: []byte("first\n\nthird"), framing: `delim:\n`, limit: 1500,
+			packets: []Packet{{Length: 5, OrigLength: 5, Data: []byte("first")}, {Length: 0, OrigLength: 0, Data: nil}, {Length: 5, OrigLength: 5, Data: []byte("third")}}},
+		{name: "Sync word", content: []byte{0x01, 0x1A, 0xCF, 0xFC, 0x1D, 0x02, 0x1A, 0x03, 0x1A, 0xCF, 0xFC, 0x1D}, framing: "delim:0x1ACFFC1D", limit: 1500,
+			packets: []Packet{{Length: 1, OrigLength: 1, Data: []byte{0x01}}, {Length: 3, OrigLength: 3, Data: []byte{0x02, 0x1A, 0x03}}}},
+		{name: "Long record", content: []byte("abcdef\ngh\n"), framing: `delim:\n`, limit: 4,
+			packets: []Packet{{Length: 4, OrigLength: 6, Data: []byte("abcd")}, {Length: 2, OrigLength: 2, Data: []byte("gh")}}},
+		{name: "One byte prefix", content: []byte{0x02, 'a', 'b', 0x00, 0x01, 'c'}, framing: "len:1", limit: 1500,
+			packets: []Packet{{Length: 2, OrigLength: 2, Data: []byte("ab")}, {Length: 0, OrigLength: 0, Data: []byte{}}, {Length: 1, OrigLength: 1, Data: []byte("c")}}},
+		{name: "Little endian prefix", content: []byte{0x03, 0x00, 'a', 'b', 'c', 0x01, 0x00, 'd'}, framing: "len:2le", limit: 2,
+			packets: []Packet{{Length: 2, OrigLength: 3, Data: []byte("ab")}, {Length: 1, OrigLength: 1, Data: []byte("d")}}},
+		{name: "Big endian prefix", content: []byte{0x00, 0x00, 0x00, 0x02, 'a', 'b'}, framing: "len:4be", limit: 1500,
+			packets: []Packet{{Length: 2, OrigLength: 2, Data: []byte("ab")}}},
+		{name: "Truncated record", content: []byte{0x00, 0x04, 'a', 'b'}, framing: "len:2be", limit: 1500,
+			packets: []Packet{{Length: 2, OrigLength: 4, Data: []byte("ab")}}},
+		{name: "Truncated prefix", content: []byte{0x00, 0x01, 'a', 0x00}, framing: "len:2be", limit: 1500,
+			packets: []Packet{{Length: 1, OrigLength: 1, Data: []byte("a")}}, err: "could not read length prefix"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			framing, err := getFraming(tc.framing)
+			if err != nil {
+				t.Fatalf("Could not get framing: %v", err)
+			}
+			f := newRegularFile(ioutil.NopCloser(bytes.NewReader(tc.content)), framing)
+			defer f.Close()
+
+			for _, expected := range tc.packets {
+				pkt, err := f.Read(context.Background(), tc.limit)
+				if err != nil {
+					t.Fatalf("Could not read packet: %v", err)
+				}
+				if pkt.Length != expected.Length || pkt.OrigLength != expected.OrigLength || !bytes.Equal(pkt.Data, expected.Data) {
+					t.Fatalf("Expected: %v \t Got: %v", expected, pkt)
+				}
+			}
+
+			_, err = f.Read(context.Background(), tc.limit)
+			if len(tc.err) != 0 {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				}
+			} else if err != io.EOF {
+				t.Fatalf("Expected: %v \t Got: %v", io.EOF, err)
+			}
+		})
+	}
+}
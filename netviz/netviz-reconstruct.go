@@ -0,0 +1,445 @@
+package netviz
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"golang.org/x/sync/errgroup"
+)
+
+// reconstructOptions represents all options for reconstruction
+type reconstructOptions struct {
+	BpP        int
+	LimitX     int
+	LimitY     int
+	Scale      int
+	Dtg        string
+	Source     string
+	Filter     string
+	LogicGate  string
+	LogicValue int
+	LayerTint  string
+	Layer      string
+	LinkType   layers.LinkType
+}
+
+// svgOptions represents various options for reconstruction
+type svgOptions struct {
+	regex             string
+	reconstructOption string
+}
+
+func createPacket(ctx context.Context, ch chan<- data, packet []int, bpP int, undo logicOp, info data) error {
+	var buf []byte
+	var tmp int
+	switch bpP {
+	case 24:
+		for _, i := range packet {
+			buf = append(buf, byte(i))
+		}
+	case 3, 6, 9, 12, 15, 18, 21:
+		var slice []int
+		for i := 0; i < len(packet); i = i + 1 {
+			if i%(bpP*8) == 0 && i != 0 {
+				bytes := createBytes(slice, bpP/3)
+				buf = append(buf, bytes...)
+				slice = slice[:0]
+			}
+			slice = append(slice, packet[i])
+		}
+		bytes := createBytes(slice, bpP/3)
+		buf = append(buf, bytes...)
+	case 1:
+		var j int
+		for i := 0; i < len(packet); i = i + 3 {
+			if j%8 == 0 && j != 0 {
+				buf = append(buf, byte(tmp))
+				tmp = 0
+			}
+			if packet[i] != 0 {
+				tmp = tmp | (1 << uint8(7-j%8))
+			}
+			j = j + 1
+		}
+		if tmp != 0 {
+			buf = append(buf, byte(tmp))
+		}
+	default:
+		return fmt.Errorf("this format is not supported so far")
+	}
+
+	if info.len > len(buf) {
+		buf = append(buf, make([]byte, info.len-len(buf))...)
+	} else if info.len > 0 {
+		buf = buf[:info.len]
+	}
+
+	info.payload = undo.gate(buf, undo.value)
+	select {
+	case ch <- info:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return nil
+}
+
+// getReverseOp returns the logical operation that undoes gate
+func getReverseOp(gate string, value int) (logicOp, error) {
+	switch strings.ToLower(gate) {
+	case "xor":
+		return logicOp{name: "xor", gate: opXor, value: byte(value)}, nil
+	case "not":
+		return logicOp{name: "not", gate: opNot, value: byte(value)}, nil
+	case "or", "and", "nand":
+		return logicOp{}, fmt.Errorf("logic gate %s is not reversible", gate)
+	case "", "none":
+		return logicOp{name: "none", gate: opDefault, value: byte(value)}, nil
+	default:
+		return logicOp{}, fmt.Errorf("unrecognized logic gate: %s", gate)
+	}
+}
+
+// getReverseLayer returns the protocol layer the packets of an image start with
+func getReverseLayer(name string) (int, error) {
+	layer, err := getLayer(name)
+	if err != nil {
+		return 0, err
+	}
+	if layer > 1 {
+		return 0, fmt.Errorf("images starting at the %s layer can't be reversed", name)
+	}
+	return layer, nil
+}
+
+// getLinkType returns the link type for packets starting at layer, that
+// have been captured with linkType
+func getLinkType(layer int, linkType layers.LinkType) layers.LinkType {
+	switch layer {
+	case 0:
+		return linkType
+	case 1:
+		return layers.LinkTypeRaw
+	}
+	return layers.LinkTypeEthernet
+}
+
+func checkVersion(parse *[]svgOptions, version string) (string, error) {
+
+	scale := svgOptions{regex: "\\s+Scale=(\\d+)$", reconstructOption: "Scale"}
+	*parse = append(*parse, scale)
+	bpP := svgOptions{regex: "\\s+BitsPerPixel=(\\d+)$", reconstructOption: "BpP"}
+	*parse = append(*parse, bpP)
+
+	switch version {
+	case "0.0.5", "0.0.4":
+		lGate := svgOptions{regex: "\\s+LogicGate=\"([a-zA-Z]*)\"$", reconstructOption: "LogicGate"}
+		*parse = append(*parse, lGate)
+		lValue := svgOptions{regex: "\\s+LogicValue=(0x[0-9A-F]{1,2})$", reconstructOption: "LogicValue"}
+		*parse = append(*parse, lValue)
+		tint := svgOptions{regex: "\\s+LayerTint=(true|false)$", reconstructOption: "LayerTint"}
+		*parse = append(*parse, tint)
+		layer := svgOptions{regex: "\\s+Layer=\"([a-z]*)\"$", reconstructOption: "Layer"}
+		*parse = append(*parse, layer)
+		fallthrough
+	case "0.0.3":
+		dtg := svgOptions{regex: "\\s+DTG=\"([^\"]*)\"$", reconstructOption: "Dtg"}
+		*parse = append(*parse, dtg)
+		source := svgOptions{regex: "\\s+Source=\"([^\"]*)\"$", reconstructOption: "Source"}
+		*parse = append(*parse, source)
+		filter := svgOptions{regex: "\\s+Filter=\"([^\"]*)\"$", reconstructOption: "Filter"}
+		*parse = append(*parse, filter)
+	default:
+		return "", fmt.Errorf("unrecognized version: %s", version)
+	}
+	return version, nil
+}
+
+func checkHeader(svg *bufio.Scanner) (reconstructOptions, error) {
+	options := reconstructOptions{LinkType: layers.LinkTypeEthernet}
+	var variant string
+	var header = false
+	var parseOptions []svgOptions
+
+	limits, err := regexp.Compile("^<svg width=\"(\\d+)\" height=\"(\\d+)\">$")
+	if err != nil {
+		return options, err
+	}
+	headerStart, err := regexp.Compile("^<!--$")
+	if err != nil {
+		return options, err
+	}
+	headerEnd, err := regexp.Compile("^-->$")
+	if err != nil {
+		return options, err
+	}
+	version, err := regexp.Compile("\\s+goNetViz \"([0-9.]+)\"$")
+	if err != nil {
+		return options, err
+	}
+	meta, err := regexp.Compile("^<metadata id=\"goNetViz\">(.*)</metadata>$")
+	if err != nil {
+		return options, err
+	}
+
+	for svg.Scan() {
+		line := svg.Text()
+		switch {
+		case options.LimitX == 0 && options.LimitY == 0 && !header:
+			matches := limits.FindStringSubmatch(line)
+			if len(matches) == 3 {
+				options.LimitX, _ = strconv.Atoi(matches[1])
+				options.LimitY, _ = strconv.Atoi(matches[2])
+			}
+		case !header:
+			// Embedded metadata replaces the header of older versions
+			if matches := meta.FindStringSubmatch(line); len(matches) == 2 {
+				metaOptions, err := getMetadataOptions(matches[1])
+				metaOptions.LimitX, metaOptions.LimitY = options.LimitX, options.LimitY
+				return metaOptions, err
+			}
+			if headerStart.MatchString(line) {
+				header = true
+			}
+		case len(variant) == 0:
+			matches := version.FindStringSubmatch(line)
+			if len(matches) == 2 {
+				variant, err = checkVersion(&parseOptions, matches[1])
+				if err != nil {
+					return options, err
+				}
+			}
+		default:
+			if headerEnd.MatchString(line) {
+				return options, nil
+			}
+			for _, parseOption := range parseOptions {
+				regex, err := regexp.Compile(parseOption.regex)
+				if err != nil {
+					return options, err
+				}
+				matches := regex.FindStringSubmatch(line)
+				if len(matches) != 2 {
+					continue
+				}
+				option := reflect.ValueOf(&options).Elem().FieldByName(parseOption.reconstructOption)
+
+				switch option.Kind() {
+				case reflect.Int:
+					new, _ := strconv.ParseInt(matches[1], 0, 64)
+					option.SetInt(new)
+				case reflect.String:
+					option.SetString(matches[1])
+				default:
+					return options, fmt.Errorf("unhandeld option type")
+				}
+				break
+			}
+		}
+	}
+	return options, fmt.Errorf("no end of header found")
+}
+
+func extractInformation(ctx context.Context, g *errgroup.Group, ch chan data, cfg configs) error {
+	defer close(ch)
+	inputfile, err := os.Open(cfg.input)
+	if err != nil {
+		return fmt.Errorf("could not open file %s: %s", cfg.input, err.Error())
+	}
+	defer inputfile.Close()
+
+	input := bufio.NewReader(inputfile)
+	if magic, err := input.Peek(len(pngMagic)); err == nil && bytes.Equal(magic, pngMagic) {
+		return extractPNGInformation(ctx, ch, input)
+	}
+
+	svg := bufio.NewScanner(input)
+	var yLast int
+	var packet []int
+
+	opt, err := checkHeader(svg)
+	if err != nil {
+		return err
+	}
+
+	if opt.LayerTint == "true" {
+		return fmt.Errorf("images with tinted layers can't be reversed")
+	}
+
+	layer, err := getReverseLayer(opt.Layer)
+	if err != nil {
+		return err
+	}
+	info := data{layer: layer, linkType: opt.LinkType}
+
+	undo, err := getReverseOp(opt.LogicGate, opt.LogicValue)
+	if err != nil {
+		return err
+	}
+
+	pixel, err := regexp.Compile("^<rect x=\"(\\d+)\" y=\"(\\d+)\" width=\"(\\d+)\" height=\"\\d+\" style=\"fill:rgb\\((\\d+),(\\d+),(\\d+)\\)\" />$")
+	if err != nil {
+		return err
+	}
+	svgEnd, err := regexp.Compile("</svg>")
+	if err != nil {
+		return err
+	}
+	groupStart, err := regexp.Compile("^<g data-toa=\"(\\d+)\" data-caplen=\"(\\d+)\" data-len=\"(\\d+)\">$")
+	if err != nil {
+		return err
+	}
+	groupEnd, err := regexp.Compile("^</g>$")
+	if err != nil {
+		return err
+	}
+
+	for svg.Scan() {
+		line := svg.Text()
+		if matches := groupStart.FindStringSubmatch(line); len(matches) == 4 {
+			info.toa, _ = strconv.ParseInt(matches[1], 10, 64)
+			info.len, _ = strconv.Atoi(matches[2])
+			info.olen, _ = strconv.Atoi(matches[3])
+			continue
+		}
+		if groupEnd.MatchString(line) {
+			if len(packet) != 0 {
+				if err := createPacket(ctx, ch, packet, opt.BpP, undo, info); err != nil {
+					return err
+				}
+				packet = packet[:0]
+			}
+			info = data{layer: layer, linkType: opt.LinkType}
+			continue
+		}
+		matches := pixel.FindStringSubmatch(line)
+		if len(matches) == 7 {
+			pixelX, _ := strconv.Atoi(matches[1])
+			pixelY, _ := strconv.Atoi(matches[2])
+			if pixelY != yLast {
+				yLast = pixelY
+				if len(packet) != 0 {
+					if err := createPacket(ctx, ch, packet, opt.BpP, undo, info); err != nil {
+						return err
+					}
+					packet = packet[:0]
+				}
+			}
+			if len(packet) == 0 && opt.Scale > 0 {
+				info.row = pixelY / opt.Scale
+			}
+			if pixelX >= opt.LimitX {
+				return fmt.Errorf("x-coordinate (%d) is bigger than the limit (%d)", pixelX, opt.LimitX)
+			}
+			width, _ := strconv.Atoi(matches[3])
+			r, _ := strconv.Atoi(matches[4])
+			g, _ := strconv.Atoi(matches[5])
+			b, _ := strconv.Atoi(matches[6])
+			// Compact images merge multiple pixels of the same color into a single element
+			run := 1
+			if opt.Scale > 0 && width > opt.Scale {
+				run = width / opt.Scale
+			}
+			for i := 0; i < run; i++ {
+				packet = append(packet, r, g, b)
+			}
+		} else if svgEnd.MatchString(line) {
+			if len(packet) != 0 {
+				return createPacket(ctx, ch, packet, opt.BpP, undo, info)
+			}
+		}
+	}
+	return nil
+}
+
+// getPcapFilename returns the name of the capture reconstructed from packets
+func getPcapFilename(packets []data, cfg configs) string {
+	var first, last time.Time
+
+	if cfg.format != "pcapng" {
+		cfg.format = "pcap"
+	}
+	if len(cfg.output) == 0 {
+		return cfg.prefix + "." + cfg.format
+	}
+	if len(packets) > 0 {
+		first = time.Unix(0, packets[0].toa)
+		last = time.Unix(0, packets[len(packets)-1].toa)
+	}
+	return getFilename(cfg, 1, first, last) + "." + cfg.format
+}
+
+func createPcap(packets []data, cfg configs) error {
+	filename := getPcapFilename(packets, cfg)
+	output, err := createFile(filename, cfg)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+	image := filepath.Base(cfg.input)
+	var w packetWriter = pcapWriter{w: pcapgo.NewWriterNanos(output)}
+	if cfg.format == "pcapng" {
+		w = pcapngWriter{w: output, name: "goNetViz", description: "Reconstructed from " + image}
+	}
+
+	linkType := layers.LinkTypeEthernet
+	if len(packets) > 0 {
+		linkType = getLinkType(packets[0].layer, packets[0].linkType)
+	}
+	if err := w.writeHeader(linkType); err != nil {
+		return fmt.Errorf("could not write header: %s", err.Error())
+	}
+
+	for _, i := range packets {
+		length := i.olen
+		if length < len(i.payload) {
+			length = len(i.payload)
+		}
+		timestamp := time.Unix(0, i.toa)
+		comment := fmt.Sprintf("%s row %d", image, i.row)
+		if err := w.writePacket(gopacket.CaptureInfo{Timestamp: timestamp, CaptureLength: len(i.payload), Length: length, InterfaceIndex: 0}, i.payload, comment); err != nil {
+			return fmt.Errorf("could not write packet: %s", err.Error())
+		}
+	}
+
+	if err := output.Close(); err != nil {
+		return fmt.Errorf("could not close file %s: %s", filename, err.Error())
+	}
+	return nil
+}
+
+func reconstruct(ctx context.Context, g *errgroup.Group, cfg configs) error {
+	ch := make(chan data)
+	var packets []data
+
+	g.Go(func() error {
+		return extractInformation(ctx, g, ch, cfg)
+	})
+
+	// The capture is written once all packets are known, as its name can
+	// depend on their timestamps
+	for i := range ch {
+		packets = append(packets, i)
+	}
+	// The context is also canceled by Wait, so it is checked before
+	interrupted := ctx.Err()
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	if interrupted != nil {
+		return interrupted
+	}
+	return createPcap(packets, cfg)
+}
@@ -0,0 +1,439 @@
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"golang.org/x/sync/errgroup"
+)
+
+func TestReconstruct(t *testing.T) {
+	tdir, ferr := ioutil.TempDir("", "reconstruct")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.RemoveAll(tdir)
+	fakePcap, ferr := ioutil.TempFile(tdir, "fake.pcap")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.Remove(fakePcap.Name())
+
+	ferr = ioutil.WriteFile(fakePcap.Name(), fakeData, 0644)
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer fakePcap.Close()
+
+	validSvgFile005, err := ioutil.TempFile(tdir, "validSvg005.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(validSvgFile005.Name())
+
+	validSvgFile005.WriteString(validSvg005)
+	if err := validSvgFile005.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	tests := []struct {
+		name string
+		cfg  configs
+		file string // Expected reconstructed capture
+		err  string
+	}{
+		{name: "solder", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "", input: fakePcap.Name(), prefix: fmt.Sprintf("%s/solder", tdir), logicOp: logic}, err: "no end of header found"},
+		{name: "Valid svg", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: reverse, scale: 1, xlimit: 1500, filter: "", input: validSvgFile005.Name(), prefix: fmt.Sprintf("%s/valid", tdir), logicOp: logic}},
+		{name: "Existing file", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: reverse, scale: 1, xlimit: 1500, filter: "", input: validSvgFile005.Name(), prefix: fmt.Sprintf("%s/valid", tdir), logicOp: logic}, err: "file exists"},
+		{name: "Overwrite", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: reverse | overwrite, scale: 1, xlimit: 1500, filter: "", input: validSvgFile005.Name(), prefix: fmt.Sprintf("%s/valid", tdir), logicOp: logic}, file: fmt.Sprintf("%s/valid.pcap", tdir)},
+		{name: "Output", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: reverse, scale: 1, xlimit: 1500, filter: "", input: validSvgFile005.Name(), prefix: fmt.Sprintf("%s/valid", tdir), output: fmt.Sprintf("%s/out/{iface}-{index}", tdir), format: "pcapng", logicOp: logic}, file: fmt.Sprintf("%s/out/%s-1.pcapng", tdir, sanitize(filepath.Base(validSvgFile005.Name())))},
+		{name: "Not writable", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: reverse, scale: 1, xlimit: 1500, filter: "", input: validSvgFile005.Name(), prefix: fmt.Sprintf("%s/valid", fakePcap.Name()), logicOp: logic}, err: "could not create directory"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g, _ := errgroup.WithContext(context.Background())
+			err := reconstruct(context.Background(), g, tc.cfg)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			if len(tc.file) != 0 {
+				if _, err := os.Stat(tc.file); err != nil {
+					t.Fatalf("Expected reconstructed capture: %v", err)
+				}
+			}
+		})
+	}
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		g, ctx := errgroup.WithContext(ctx)
+		cfg := configs{bpP: 1, flags: reverse, scale: 1, xlimit: 1500, input: validSvgFile005.Name(), prefix: fmt.Sprintf("%s/canceled", tdir), logicOp: logic}
+		if err := reconstruct(ctx, g, cfg); err != context.Canceled {
+			t.Fatalf("Expected: %v \t Got: %v", context.Canceled, err)
+		}
+		if _, err := os.Stat(fmt.Sprintf("%s/canceled.pcap", tdir)); !os.IsNotExist(err) {
+			t.Fatalf("Expected no capture, got: %v", err)
+		}
+	})
+}
+
+func TestCreatePcap(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestCreatePcap")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	tests := []struct {
+		name     string
+		payload  []byte
+		layer    int
+		linkType layers.LinkType
+		cfg      configs
+		err      string
+	}{
+		{name: "Simple", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: "", prefix: fmt.Sprintf("%s/simple", dir), logicOp: logic}, payload: []byte{0x00, 0x01, 0x02, 0x03, 0x04, 0x05}, linkType: layers.LinkTypeEthernet},
+		{name: "Network layer", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: "", prefix: fmt.Sprintf("%s/network", dir), logicOp: logic}, payload: []byte{0x45, 0x00, 0x00, 0x14}, layer: 1, linkType: layers.LinkTypeRaw},
+		{name: "Linux SLL", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: "", prefix: fmt.Sprintf("%s/sll", dir), logicOp: logic}, payload: []byte{0x00, 0x00, 0x00, 0x01, 0x00, 0x06}, linkType: layers.LinkTypeLinuxSLL},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			packets := []data{{toa: 1257894000000000123, len: len(tc.payload), olen: 1500, layer: tc.layer, linkType: tc.linkType, payload: tc.payload}}
+			err := createPcap(packets, tc.cfg)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			f, err := os.Open(fmt.Sprintf("%s.pcap", tc.cfg.prefix))
+			if err != nil {
+				t.Fatalf("Could not open pcap: %v", err)
+			}
+			defer f.Close()
+			r, err := pcapgo.NewReader(f)
+			if err != nil {
+				t.Fatalf("Could not read pcap: %v", err)
+			}
+			if r.LinkType() != tc.linkType {
+				t.Fatalf("Expected: %v \t Got: %v", tc.linkType, r.LinkType())
+			}
+			payload, ci, err := r.ReadPacketData()
+			if err != nil {
+				t.Fatalf("Could not read packet: %v", err)
+			}
+			if !bytes.Equal(payload, tc.payload) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.payload, payload)
+			}
+			if ci.Timestamp.UnixNano() != 1257894000000000123 || ci.CaptureLength != len(tc.payload) || ci.Length != 1500 {
+				t.Fatalf("Unexpected capture information: %v", ci)
+			}
+		})
+	}
+}
+
+func TestCreatePacket(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestCreatePcap")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logic := logicOp{
+		name:  "none",
+		gate:  opDefault,
+		value: 0,
+	}
+
+	tests := []struct {
+		name   string
+		recv   []byte
+		packet []int
+		bpP    int
+		info   data
+		err    string
+	}{
+		{name: "24 BitsPerPixel", recv: []byte{8, 16, 32, 64, 128}, packet: []int{8, 16, 32, 64, 128}, bpP: 24},
+		{name: "12 BitPerPixel", recv: []byte{1, 2, 5, 13, 18, 57, 153}, packet: []int{0, 1, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89, 144, 233}, bpP: 12},
+		{name: "3 BitPerPixel", recv: []byte{5, 49, 14}, packet: []int{0, 1, 1, 2, 3, 5, 8, 13, 21, 34, 55, 89, 144, 233, 144, 89, 55, 34, 21, 13, 8, 5, 3, 2, 1, 1, 0}, bpP: 3},
+		{name: "2 BitsPerPixel", recv: []byte{}, packet: []int{8, 16, 32, 64, 128}, bpP: 2, err: "this format is not supported so far"},
+		{name: "1 BitPerPixel", recv: []byte{1, 8}, packet: []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 255, 255, 255, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 255, 255, 255, 0, 0, 0, 0, 0, 0, 0, 0, 0}, bpP: 1},
+		{name: "Truncated", recv: []byte{8, 16, 32}, packet: []int{8, 16, 32, 64, 128, 0}, bpP: 24, info: data{toa: 1, len: 3, olen: 60}},
+		{name: "Padded", recv: []byte{1, 8, 0, 0}, packet: []int{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 255, 255, 255, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 255, 255, 255, 0, 0, 0, 0, 0, 0, 0, 0, 0}, bpP: 1, info: data{toa: 1, len: 4, olen: 4}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ch := make(chan data)
+			var wg sync.WaitGroup
+			wg.Add(1)
+			var recv []byte
+			go func() {
+				defer wg.Done()
+				v := <-ch
+				recv = append(recv, v.payload...)
+				close(ch)
+			}()
+			err := createPacket(context.Background(), ch, tc.packet, tc.bpP, logic, tc.info)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					wg.Done()
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			wg.Wait()
+			if !bytes.Equal(recv, tc.recv) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.recv, recv)
+			}
+		})
+	}
+}
+
+func TestExtractInformation(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestExtractInformation")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	notSvgFile, err := ioutil.TempFile(dir, "notSvg.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(notSvgFile.Name())
+
+	notSvgFile.WriteString(notSvg)
+	if err := notSvgFile.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	withoutCommentFile, err := ioutil.TempFile(dir, "withoutComment.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(withoutCommentFile.Name())
+
+	withoutCommentFile.WriteString(withoutComment)
+	if err := withoutCommentFile.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	validSvgFile003, err := ioutil.TempFile(dir, "validSvg003.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(validSvgFile003.Name())
+
+	validSvgFile003.WriteString(validSvg003)
+	if err := validSvgFile003.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	validSvgFile004, err := ioutil.TempFile(dir, "validSvg004.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(validSvgFile004.Name())
+
+	validSvgFile004.WriteString(validSvg004)
+	if err := validSvgFile004.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	xorSvgFile004, err := ioutil.TempFile(dir, "xorSvg004.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(xorSvgFile004.Name())
+
+	xorSvgFile004.WriteString(xorSvg004)
+	if err := xorSvgFile004.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	lossySvgFile004, err := ioutil.TempFile(dir, "lossySvg004.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(lossySvgFile004.Name())
+
+	lossySvgFile004.WriteString(lossySvg004)
+	if err := lossySvgFile004.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	validSvgFile005, err := ioutil.TempFile(dir, "validSvg005.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(validSvgFile005.Name())
+
+	validSvgFile005.WriteString(validSvg005)
+	if err := validSvgFile005.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	compactSvgFile005, err := ioutil.TempFile(dir, "compactSvg005.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(compactSvgFile005.Name())
+
+	compactSvgFile005.WriteString(compactSvg005)
+	if err := compactSvgFile005.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	tintedSvgFile005, err := ioutil.TempFile(dir, "tintedSvg005.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(tintedSvgFile005.Name())
+
+	tintedSvgFile005.WriteString(tintedSvg005)
+	if err := tintedSvgFile005.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	networkSvgFile005, err := ioutil.TempFile(dir, "networkSvg005.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(networkSvgFile005.Name())
+
+	networkSvgFile005.WriteString(fmt.Sprintf(layerSvg005, "network"))
+	if err := networkSvgFile005.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	payloadSvgFile005, err := ioutil.TempFile(dir, "payloadSvg005.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(payloadSvgFile005.Name())
+
+	payloadSvgFile005.WriteString(fmt.Sprintf(layerSvg005, "payload"))
+	if err := payloadSvgFile005.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	metadataSvgFile, err := ioutil.TempFile(dir, "metadata.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(metadataSvgFile.Name())
+
+	metadataSvgFile.WriteString(metadataSvg)
+	if err := metadataSvgFile.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	invalidVersionFile, err := ioutil.TempFile(dir, "invalidVersion.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(invalidVersionFile.Name())
+
+	invalidVersionFile.WriteString(invalidVersion)
+	if err := invalidVersionFile.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	tests := []struct {
+		name string
+		cfg  configs
+		recv []byte
+		err  string
+	}{
+		{name: "No file", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: "noFile", prefix: fmt.Sprintf("%s/noFile", dir), logicOp: logic}, err: "could not open file"},
+		{name: "Not a svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: notSvgFile.Name(), prefix: fmt.Sprintf("%s/not_a_svg", dir), logicOp: logic}, err: "no end of header found"},
+		{name: "Without Comment", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: withoutCommentFile.Name(), prefix: fmt.Sprintf("%s/without_comment", dir), logicOp: logic}, err: "no end of header found"},
+		{name: "Valid003 svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: validSvgFile003.Name(), prefix: fmt.Sprintf("%s/valid_003_svg", dir), logicOp: logic}, recv: []byte{0, 0}},
+		{name: "Valid004 svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: validSvgFile004.Name(), prefix: fmt.Sprintf("%s/valid_004_svg", dir), logicOp: logic}, recv: []byte{255, 0, 0, 0, 255, 0, 0, 0, 255, 0, 255, 0, 255, 0, 0, 0, 255, 0, 0, 0, 255, 0, 255, 0, 255, 0, 0, 0, 255, 0, 0, 0, 255, 0, 255, 0}},
+		{name: "Xor004 svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: xorSvgFile004.Name(), prefix: fmt.Sprintf("%s/xor_004_svg", dir), logicOp: logic}, recv: []byte{0, 1, 2, 255, 240, 15}},
+		{name: "Lossy004 svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: lossySvgFile004.Name(), prefix: fmt.Sprintf("%s/lossy_004_svg", dir), logicOp: logic}, err: "logic gate or is not reversible"},
+		{name: "Valid005 svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: validSvgFile005.Name(), prefix: fmt.Sprintf("%s/valid_005_svg", dir), logicOp: logic}, recv: []byte{1, 2, 3, 4, 5, 6, 7, 0xCA, 0xFE}},
+		{name: "Compact005 svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: compactSvgFile005.Name(), prefix: fmt.Sprintf("%s/compact_005_svg", dir), logicOp: logic}, recv: []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 1, 2, 3, 0xCA, 0xFE, 0}},
+		{name: "Tinted005 svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: tintedSvgFile005.Name(), prefix: fmt.Sprintf("%s/tinted_005_svg", dir), logicOp: logic}, err: "images with tinted layers can't be reversed"},
+		{name: "Network005 svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: networkSvgFile005.Name(), prefix: fmt.Sprintf("%s/network_005_svg", dir), logicOp: logic}, recv: []byte{0x45, 0x00, 0x00}},
+		{name: "Payload005 svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: payloadSvgFile005.Name(), prefix: fmt.Sprintf("%s/payload_005_svg", dir), logicOp: logic}, err: "images starting at the payload layer can't be reversed"},
+		{name: "Metadata svg", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: metadataSvgFile.Name(), prefix: fmt.Sprintf("%s/metadata_svg", dir), logicOp: logic}, recv: []byte{1, 2, 3}},
+		{name: "Invalid version", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "", input: invalidVersionFile.Name(), prefix: fmt.Sprintf("%s/invalid_version", dir), logicOp: logic}, err: "unrecognized version"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g, _ := errgroup.WithContext(context.Background())
+			var wg sync.WaitGroup
+			wg.Add(1)
+			ch := make(chan data)
+			var recv []byte
+			go func() {
+				defer wg.Done()
+				for i, ok := <-ch; ok; i, ok = <-ch {
+					recv = append(recv, i.payload...)
+				}
+			}()
+			err := extractInformation(context.Background(), g, ch, tc.cfg)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			wg.Wait()
+			if !bytes.Equal(recv, tc.recv) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.recv, recv)
+			}
+		})
+	}
+}
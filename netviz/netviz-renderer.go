@@ -0,0 +1,207 @@
+package netviz
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Pixel represents a single point of the visualization
+type Pixel struct {
+	X, Y    int
+	R, G, B uint8
+}
+
+// Row represents the information of a single packet within the visualization
+type Row struct {
+	Index   uint    // Number of the packet within the source
+	Arrival int64   // Timestamp of arrival in nanoseconds
+	CapLen  int     // Number of bytes of the packet within the visualization
+	Len     int     // Original length of the packet
+	Data    []byte  // Visualized bytes of the packet
+	Pixels  []Pixel // Pixels of the packet
+}
+
+// Image describes a single visualization
+type Image struct {
+	Name   string // Name of the resulting file without extension
+	Width  int    // Number of pixels per row
+	Height int    // Number of rows
+}
+
+// Renderer writes visualizations to an output backend.
+// For each image Begin is called once, followed by AddRow for every packet
+// and End to finish the image. Other backends are set with WithRenderer.
+type Renderer interface {
+	Begin(img Image) error
+	AddRow(r Row) error
+	End() error
+}
+
+func newRenderer(cfg configs) Renderer {
+	if cfg.renderer != nil {
+		return cfg.renderer
+	}
+	switch cfg.format {
+	case "png":
+		return &pngRenderer{cfg: cfg}
+	case "html":
+		return &htmlRenderer{cfg: cfg}
+	case "terminal":
+		return newTerminalRenderer(os.Stdout, cfg.terminalOpts)
+	default:
+		return &svgRenderer{cfg: cfg}
+	}
+}
+
+func render(r Renderer, img Image, rows []Row) error {
+	if err := r.Begin(img); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := r.AddRow(row); err != nil {
+			return err
+		}
+	}
+	return r.End()
+}
+
+// Space in pixels for the axes of svg images
+const (
+	axesLeft = 170
+	axesTop  = 20
+)
+
+// Minimal distance in pixels between two labels of the axes
+const (
+	axesRowDistance    = 10
+	axesOffsetDistance = 50
+)
+
+// svgRenderer writes each image into a svg file
+type svgRenderer struct {
+	cfg       configs
+	img       Image
+	rows      []Row
+	content   bytes.Buffer
+	labels    bytes.Buffer // Packet index and timestamp of the rows
+	nextLabel int          // Smallest y-coordinate for the next label
+}
+
+func (s *svgRenderer) Begin(img Image) error {
+	s.img = img
+	s.rows = s.rows[:0]
+	s.content.Reset()
+	s.labels.Reset()
+	s.nextLabel = 0
+	return nil
+}
+
+func (s *svgRenderer) AddRow(r Row) error {
+	scale := int(s.cfg.scale)
+	s.rows = append(s.rows, r)
+	fmt.Fprintf(&s.content, "<g data-toa=\"%d\" data-caplen=\"%d\" data-len=\"%d\">\n", r.Arrival, r.CapLen, r.Len)
+	if (s.cfg.flags&axes) == axes && len(r.Pixels) > 0 {
+		toa := time.Unix(0, r.Arrival).UTC().Format("15:04:05.000000")
+		fmt.Fprintf(&s.content, "<title>Packet %d | %s | %d of %d bytes | % x</title>\n", r.Index, toa, r.CapLen, r.Len, r.Data)
+		if y := r.Pixels[0].Y * scale; y >= s.nextLabel {
+			fmt.Fprintf(&s.labels, "<text x=\"%d\" y=\"%d\" dominant-baseline=\"hanging\">%d %s</text>\n", 2, axesTop+y, r.Index, toa)
+			s.nextLabel = y + axesRowDistance
+		}
+	}
+	for i := 0; i < len(r.Pixels); {
+		p := r.Pixels[i]
+		run := 1
+		if (s.cfg.flags & compact) == compact {
+			for i+run < len(r.Pixels) && r.Pixels[i+run].R == p.R && r.Pixels[i+run].G == p.G && r.Pixels[i+run].B == p.B {
+				run++
+			}
+		}
+		fmt.Fprintf(&s.content, "<rect x=\"%d\" y=\"%d\" width=\"%d\" height=\"%d\" style=\"fill:rgb(%d,%d,%d)\" />\n", p.X*scale, p.Y*scale, run*scale, scale, p.R, p.G, p.B)
+		i += run
+	}
+	s.content.WriteString("</g>\n")
+	return nil
+}
+
+// ruler returns a ruler with the byte offsets of the pixels
+func (s *svgRenderer) ruler() string {
+	var ruler bytes.Buffer
+	scale := int(s.cfg.scale)
+
+	// Pick the smallest step out of 1, 2, 5, 10, 20, 50, ... that keeps the labels apart
+	step, magnitude := 1, 1
+	for i := 1; step*scale < axesOffsetDistance; i++ {
+		if i%3 == 0 {
+			magnitude *= 10
+		}
+		step = []int{1, 2, 5}[i%3] * magnitude
+	}
+
+	fmt.Fprintf(&ruler, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"black\" />\n", axesLeft, axesTop-1, axesLeft+s.img.Width*scale, axesTop-1)
+	for x := 0; x < s.img.Width; x += step {
+		fmt.Fprintf(&ruler, "<line x1=\"%d\" y1=\"%d\" x2=\"%d\" y2=\"%d\" stroke=\"black\" />\n", axesLeft+x*scale, axesTop-5, axesLeft+x*scale, axesTop-1)
+		fmt.Fprintf(&ruler, "<text x=\"%d\" y=\"%d\">%d</text>\n", axesLeft+x*scale+2, axesTop-6, x*int(s.cfg.bpP)/8)
+	}
+	return ruler.String()
+}
+
+func (s *svgRenderer) End() error {
+	scale := int(s.cfg.scale)
+	width, height := s.img.Width*scale, s.img.Height*scale
+	content := s.content.String()
+
+	if (s.cfg.flags&axes) == axes && len(content) != 0 {
+		content = fmt.Sprintf("<g font-family=\"monospace\" font-size=\"10\">\n%s%s</g>\n<g transform=\"translate(%d,%d)\">\n%s</g>\n",
+			s.ruler(), s.labels.String(), axesLeft, axesTop, content)
+		width += axesLeft
+		height += axesTop
+	}
+	return createImage(s.img.Name+".svg", width, height, content, s.rows, s.cfg)
+}
+
+// pngRenderer writes each image into a png file
+type pngRenderer struct {
+	cfg  configs
+	img  Image
+	rows []Row
+}
+
+func (p *pngRenderer) Begin(img Image) error {
+	p.img = img
+	p.rows = p.rows[:0]
+	return nil
+}
+
+func (p *pngRenderer) AddRow(r Row) error {
+	p.rows = append(p.rows, r)
+	return nil
+}
+
+func (p *pngRenderer) End() error {
+	scale := int(p.cfg.scale)
+	return createPNG(p.img.Name+".png", p.img.Width*scale, p.img.Height*scale, p.rows, p.cfg)
+}
+
+// htmlRenderer writes each image into a self-contained html report
+type htmlRenderer struct {
+	cfg  configs
+	img  Image
+	rows []Row
+}
+
+func (h *htmlRenderer) Begin(img Image) error {
+	h.img = img
+	h.rows = h.rows[:0]
+	return nil
+}
+
+func (h *htmlRenderer) AddRow(r Row) error {
+	h.rows = append(h.rows, r)
+	return nil
+}
+
+func (h *htmlRenderer) End() error {
+	return createHTML(h.img.Name+".html", h.img.Width, h.img.Height, h.rows, h.cfg)
+}
@@ -0,0 +1,148 @@
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"golang.org/x/sync/errgroup"
+)
+
+func TestNewRenderer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		format   string
+		renderer Renderer
+	}{
+		{name: "Default", format: "", renderer: &svgRenderer{}},
+		{name: "svg", format: "svg", renderer: &svgRenderer{}},
+		{name: "png", format: "png", renderer: &pngRenderer{}},
+		{name: "html", format: "html", renderer: &htmlRenderer{}},
+		{name: "terminal", format: "terminal", renderer: &terminalRenderer{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := newRenderer(configs{format: tc.format})
+			if fmt.Sprintf("%T", r) != fmt.Sprintf("%T", tc.renderer) {
+				t.Fatalf("Expected: %T \t Got: %T", tc.renderer, r)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestRender")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(fmt.Sprintf("%s/file", dir), nil, 0644); err != nil {
+		t.Fatalf("Could not create file: %v", err)
+	}
+
+	logic := logicOp{name: "none"}
+	rows := []Row{{Arrival: 1, CapLen: 3, Len: 3, Pixels: []Pixel{{X: 0, Y: 0, R: 0xCA, G: 0xFE, B: 0xC0}}}}
+
+	tests := []struct {
+		name string
+		img  Image
+		rows []Row
+		cfg  configs
+		file string
+		err  string
+	}{
+		{name: "svg", img: Image{Name: fmt.Sprintf("%s/svg", dir), Width: 1, Height: 1}, rows: rows, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "svg", logicOp: logic}, file: fmt.Sprintf("%s/svg.svg", dir)},
+		{name: "png", img: Image{Name: fmt.Sprintf("%s/png", dir), Width: 1, Height: 1}, rows: rows, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "png", logicOp: logic}, file: fmt.Sprintf("%s/png.png", dir)},
+		{name: "Empty svg", img: Image{Name: fmt.Sprintf("%s/empty", dir)}, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "svg", logicOp: logic}, err: "no content to write"},
+		{name: "Existing file", img: Image{Name: fmt.Sprintf("%s/svg", dir), Width: 1, Height: 1}, rows: rows, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "svg", logicOp: logic}, err: "could not open file"},
+		{name: "Overwrite", img: Image{Name: fmt.Sprintf("%s/svg", dir), Width: 1, Height: 1}, rows: rows, cfg: configs{bpP: 24, flags: solder | overwrite, scale: 1, xlimit: 1500, input: "input", format: "svg", logicOp: logic}, file: fmt.Sprintf("%s/svg.svg", dir)},
+		{name: "Missing directory", img: Image{Name: fmt.Sprintf("%s/missing/svg", dir), Width: 1, Height: 1}, rows: rows, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "svg", logicOp: logic}, file: fmt.Sprintf("%s/missing/svg.svg", dir)},
+		{name: "Not writable", img: Image{Name: fmt.Sprintf("%s/file/svg", dir), Width: 1, Height: 1}, rows: rows, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, input: "input", format: "svg", logicOp: logic}, err: "could not create directory"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := render(newRenderer(tc.cfg), tc.img, tc.rows)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			if _, err := os.Stat(tc.file); err != nil {
+				t.Fatalf("Expected file %s: %v", tc.file, err)
+			}
+		})
+	}
+}
+
+func TestSVGAxes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestSVGAxes")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	cfg := configs{bpP: 24, flags: solder | axes, scale: 2, xlimit: 1500, input: "input", prefix: fmt.Sprintf("%s/axes", dir), format: "svg", layer: "link", logicOp: logicOp{name: "none", gate: opDefault}}
+	content := []data{
+		{num: 1, toa: 1500000000000001000, len: 6, olen: 6, payload: []byte{0xCA, 0xFE, 0xC0, 0x00, 0x10, 0xFF}},
+		{num: 3, toa: 1500000000000002000, len: 3, olen: 60, payload: []byte{0x01, 0x02, 0x03}},
+	}
+	g, _ := errgroup.WithContext(context.Background())
+	createVisualization(g, content, 1, cfg)
+	if err := g.Wait(); err != nil {
+		t.Fatalf("Could not create image: %v", err)
+	}
+
+	svg, err := ioutil.ReadFile(fmt.Sprintf("%s/axes-1.svg", dir))
+	if err != nil {
+		t.Fatalf("Could not read image: %v", err)
+	}
+	for _, expected := range []string{
+		fmt.Sprintf("<svg width=\"%d\" height=\"%d\">", 2*2+axesLeft, 2*2+axesTop),
+		"<text x=\"172\" y=\"14\">0</text>",
+		"<text x=\"2\" y=\"20\" dominant-baseline=\"hanging\">1 02:40:00.000001</text>",
+		"<title>Packet 3 | 02:40:00.000002 | 3 of 60 bytes | 01 02 03</title>",
+		fmt.Sprintf("<g transform=\"translate(%d,%d)\">", axesLeft, axesTop),
+	} {
+		if !strings.Contains(string(svg), expected) {
+			t.Fatalf("Expected %q in:\n%s", expected, svg)
+		}
+	}
+	// The second row is too close to the first one for a label of its own
+	if strings.Contains(string(svg), "3 02:40:00.000002</text>") {
+		t.Fatalf("Expected no label for the second row:\n%s", svg)
+	}
+
+	// Images with axes can still be reversed
+	cfg.input = fmt.Sprintf("%s/axes-1.svg", dir)
+	ch := make(chan data)
+	var recv []byte
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range ch {
+			recv = append(recv, i.payload...)
+		}
+	}()
+	err = extractInformation(context.Background(), g, ch, cfg)
+	<-done
+	if err != nil {
+		t.Fatalf("Could not reverse image: %v", err)
+	}
+	if expected := []byte{0xCA, 0xFE, 0xC0, 0x00, 0x10, 0xFF, 0x01, 0x02, 0x03}; !bytes.Equal(recv, expected) {
+		t.Fatalf("Expected: %v \t Got: %v", expected, recv)
+	}
+}
@@ -0,0 +1,264 @@
+package netviz
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// palette prints the colors of two pixels above each other as a single character
+type palette func(top, bottom [3]uint8) string
+
+// palettes holds the supported palettes for output on the terminal
+var palettes = map[string]palette{
+	"truecolor": trueColor,
+	"256":       color256,
+	"16":        color16,
+	"ascii":     asciiShade,
+}
+
+// cubeLevels holds the intensities of the 6x6x6 color cube of 256 color terminals
+var cubeLevels = [6]int{0x00, 0x5F, 0x87, 0xAF, 0xD7, 0xFF}
+
+// ansiColors holds the 16 colors of the standard and bright ANSI palette
+var ansiColors = [16][3]uint8{
+	{0x00, 0x00, 0x00}, {0x80, 0x00, 0x00}, {0x00, 0x80, 0x00}, {0x80, 0x80, 0x00},
+	{0x00, 0x00, 0x80}, {0x80, 0x00, 0x80}, {0x00, 0x80, 0x80}, {0xC0, 0xC0, 0xC0},
+	{0x80, 0x80, 0x80}, {0xFF, 0x00, 0x00}, {0x00, 0xFF, 0x00}, {0xFF, 0xFF, 0x00},
+	{0x00, 0x00, 0xFF}, {0xFF, 0x00, 0xFF}, {0x00, 0xFF, 0xFF}, {0xFF, 0xFF, 0xFF},
+}
+
+// asciiShades holds characters ordered from dark to bright
+const asciiShades = " .:-=+*#%@"
+
+func distance(c [3]uint8, r, g, b int) int {
+	dr, dg, db := int(c[0])-r, int(c[1])-g, int(c[2])-b
+	return dr*dr + dg*dg + db*db
+}
+
+func luminance(c [3]uint8) int {
+	return (299*int(c[0]) + 587*int(c[1]) + 114*int(c[2])) / 1000
+}
+
+func trueColor(top, bottom [3]uint8) string {
+	return fmt.Sprintf("\x1B[48;2;%d;%d;%dm\x1B[38;2;%d;%d;%dm▀", bottom[0], bottom[1], bottom[2], top[0], top[1], top[2])
+}
+
+func cubeIndex(v uint8) int {
+	switch {
+	case v < 48:
+		return 0
+	case v < 115:
+		return 1
+	default:
+		return (int(v) - 35) / 40
+	}
+}
+
+// to256 returns the closest color of the color cube or the grayscale ramp
+func to256(c [3]uint8) int {
+	r, g, b := cubeIndex(c[0]), cubeIndex(c[1]), cubeIndex(c[2])
+	cube := distance(c, cubeLevels[r], cubeLevels[g], cubeLevels[b])
+
+	gray := ((int(c[0])+int(c[1])+int(c[2]))/3 - 3) / 10
+	if gray < 0 {
+		gray = 0
+	} else if gray > 23 {
+		gray = 23
+	}
+	level := 8 + 10*gray
+	if distance(c, level, level, level) < cube {
+		return 232 + gray
+	}
+	return 16 + 36*r + 6*g + b
+}
+
+func color256(top, bottom [3]uint8) string {
+	return fmt.Sprintf("\x1B[48;5;%dm\x1B[38;5;%dm▀", to256(bottom), to256(top))
+}
+
+// to16 returns the closest color of the ANSI palette
+func to16(c [3]uint8) int {
+	var closest int
+	for i := range ansiColors {
+		a := ansiColors[i]
+		if distance(c, int(a[0]), int(a[1]), int(a[2])) < distance(c, int(ansiColors[closest][0]), int(ansiColors[closest][1]), int(ansiColors[closest][2])) {
+			closest = i
+		}
+	}
+	return closest
+}
+
+func color16(top, bottom [3]uint8) string {
+	fg, bg := to16(top), to16(bottom)
+	if fg < 8 {
+		fg += 30
+	} else {
+		fg += 90 - 8
+	}
+	if bg < 8 {
+		bg += 40
+	} else {
+		bg += 100 - 8
+	}
+	return fmt.Sprintf("\x1B[%dm\x1B[%dm▀", bg, fg)
+}
+
+func asciiShade(top, bottom [3]uint8) string {
+	lum := (luminance(top) + luminance(bottom)) / 2
+	return string(asciiShades[lum*(len(asciiShades)-1)/255])
+}
+
+// detectColors returns the palette supported by the terminal
+func detectColors() string {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return "truecolor"
+	}
+	term := os.Getenv("TERM")
+	switch {
+	case term == "" || term == "dumb":
+		return "ascii"
+	case strings.Contains(term, "256color"):
+		return "256"
+	}
+	return "16"
+}
+
+// terminalSize returns the number of characters per line and the number of
+// lines of the terminal
+func terminalSize() (int, int) {
+	width, height := ttySize(os.Stdout)
+	if width <= 0 {
+		width = 80
+		if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+			width = w
+		}
+	}
+	if height <= 0 {
+		height = 24
+		if h, err := strconv.Atoi(os.Getenv("LINES")); err == nil && h > 0 {
+			height = h
+		}
+	}
+	return width, height
+}
+
+// terminalRenderer prints two rows per line on the terminal
+type terminalRenderer struct {
+	out     io.Writer
+	width   int // Number of characters per line
+	wrap    bool
+	palette palette
+	reset   string
+	factor  int // Number of pixels merged into a single character
+	top     *Row
+}
+
+func newTerminalRenderer(out io.Writer, opts terminalOpts) *terminalRenderer {
+	t := &terminalRenderer{out: out, width: opts.width, wrap: opts.wrap, factor: 1}
+	if t.width <= 0 {
+		t.width, _ = terminalSize()
+	}
+	colors := opts.colors
+	if _, ok := palettes[colors]; !ok {
+		colors = detectColors()
+	}
+	t.palette = palettes[colors]
+	if colors != "ascii" {
+		t.reset = "\x1B[m"
+	}
+	return t
+}
+
+func (t *terminalRenderer) Begin(img Image) error {
+	t.top = nil
+	t.factor = 1
+	if !t.wrap && img.Width > t.width {
+		t.factor = (img.Width + t.width - 1) / t.width
+	}
+	return nil
+}
+
+func (t *terminalRenderer) AddRow(r Row) error {
+	if t.top == nil {
+		t.top = &r
+		return nil
+	}
+	top := t.top
+	t.top = nil
+	return t.printLine(top, &r)
+}
+
+func (t *terminalRenderer) End() error {
+	if t.top == nil {
+		return nil
+	}
+	top := t.top
+	t.top = nil
+	return t.printLine(top, nil)
+}
+
+// downscale merges t.factor pixels of r into a single color
+func (t *terminalRenderer) downscale(r *Row) [][3]uint8 {
+	var colors [][3]uint8
+
+	if r == nil {
+		return nil
+	}
+	for i := 0; i < len(r.Pixels); i += t.factor {
+		var sum [3]int
+		end := i + t.factor
+		if end > len(r.Pixels) {
+			end = len(r.Pixels)
+		}
+		for _, p := range r.Pixels[i:end] {
+			sum[0] += int(p.R)
+			sum[1] += int(p.G)
+			sum[2] += int(p.B)
+		}
+		n := end - i
+		colors = append(colors, [3]uint8{uint8(sum[0] / n), uint8(sum[1] / n), uint8(sum[2] / n)})
+	}
+	return colors
+}
+
+// printLine prints the upper half of each character in the color of top and
+// the lower half in the color of bottom. Missing pixels are black.
+// Rows wider than the terminal are continued on the next line.
+func (t *terminalRenderer) printLine(top, bottom *Row) error {
+	var line bytes.Buffer
+
+	upper := t.downscale(top)
+	lower := t.downscale(bottom)
+	width := len(upper)
+	if len(lower) > width {
+		width = len(lower)
+	}
+
+	for start := 0; start < width; start += t.width {
+		end := start + t.width
+		if end > width {
+			end = width
+		}
+		for x := start; x < end; x++ {
+			var c1, c2 [3]uint8
+			if x < len(upper) {
+				c1 = upper[x]
+			}
+			if x < len(lower) {
+				c2 = lower[x]
+			}
+			line.WriteString(t.palette(c1, c2))
+		}
+		line.WriteString(t.reset + "\n")
+	}
+
+	if _, err := t.out.Write(line.Bytes()); err != nil {
+		return fmt.Errorf("could not write to terminal: %s", err.Error())
+	}
+	return nil
+}
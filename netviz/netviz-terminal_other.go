@@ -0,0 +1,19 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package netviz
+
+import (
+	"fmt"
+	"os"
+)
+
+// ttySize returns 0 as the size of the terminal can't be detected on this platform
+func ttySize(f *os.File) (int, int) {
+	return 0, 0
+}
+
+// makeRaw returns an error as raw mode is not supported on this platform
+func makeRaw(fd int) (func(), error) {
+	return nil, fmt.Errorf("raw mode is not supported on this platform")
+}
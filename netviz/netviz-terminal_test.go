@@ -0,0 +1,129 @@
+package netviz
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTerminalRenderer(t *testing.T) {
+	t.Parallel()
+
+	cfg := configs{bpP: 24, flags: terminal, scale: 1, xlimit: 1500, format: "terminal"}
+	long := packetToRow(data{len: 6, payload: []byte{0xCA, 0xFE, 0xC0, 0x00, 0x10, 0xFF}}, 0, cfg)
+	short := packetToRow(data{len: 3, payload: []byte{0x01, 0x02, 0x03}}, 1, cfg)
+	truecolor := terminalOpts{width: 80, colors: "truecolor"}
+
+	tests := []struct {
+		name  string
+		opts  terminalOpts
+		width int
+		rows  []Row
+		lines []string
+	}{
+		{name: "No rows", opts: truecolor, width: 2, rows: nil, lines: nil},
+		{name: "Single row", opts: truecolor, width: 2, rows: []Row{long}, lines: []string{"\x1B[48;2;0;0;0m\x1B[38;2;202;254;192m▀\x1B[48;2;0;0;0m\x1B[38;2;0;16;255m▀\x1B[m"}},
+		{name: "Longer top row", opts: truecolor, width: 2, rows: []Row{long, short}, lines: []string{"\x1B[48;2;1;2;3m\x1B[38;2;202;254;192m▀\x1B[48;2;0;0;0m\x1B[38;2;0;16;255m▀\x1B[m"}},
+		{name: "Longer bottom row", opts: truecolor, width: 2, rows: []Row{short, long}, lines: []string{"\x1B[48;2;202;254;192m\x1B[38;2;1;2;3m▀\x1B[48;2;0;16;255m\x1B[38;2;0;0;0m▀\x1B[m"}},
+		{name: "Three rows", opts: truecolor, width: 2, rows: []Row{short, short, long}, lines: []string{"\x1B[48;2;1;2;3m\x1B[38;2;1;2;3m▀\x1B[m", "\x1B[48;2;0;0;0m\x1B[38;2;202;254;192m▀\x1B[48;2;0;0;0m\x1B[38;2;0;16;255m▀\x1B[m"}},
+		{name: "Downscale", opts: terminalOpts{width: 1, colors: "truecolor"}, width: 2, rows: []Row{long}, lines: []string{"\x1B[48;2;0;0;0m\x1B[38;2;101;135;223m▀\x1B[m"}},
+		{name: "Wrap", opts: terminalOpts{width: 1, wrap: true, colors: "truecolor"}, width: 2, rows: []Row{long}, lines: []string{"\x1B[48;2;0;0;0m\x1B[38;2;202;254;192m▀\x1B[m", "\x1B[48;2;0;0;0m\x1B[38;2;0;16;255m▀\x1B[m"}},
+		{name: "256 colors", opts: terminalOpts{width: 80, colors: "256"}, width: 2, rows: []Row{long, short}, lines: []string{"\x1B[48;5;16m\x1B[38;5;193m▀\x1B[48;5;16m\x1B[38;5;21m▀\x1B[m"}},
+		{name: "16 colors", opts: terminalOpts{width: 80, colors: "16"}, width: 2, rows: []Row{long, short}, lines: []string{"\x1B[40m\x1B[37m▀\x1B[40m\x1B[94m▀\x1B[m"}},
+		{name: "ASCII", opts: terminalOpts{width: 80, colors: "ascii"}, width: 2, rows: []Row{long, short}, lines: []string{"= "}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := render(newTerminalRenderer(&out, tc.opts), Image{Width: tc.width}, tc.rows); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var lines []string
+			if out.Len() > 0 {
+				lines = strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+			}
+			if len(lines) != len(tc.lines) {
+				t.Fatalf("Expected %d lines \t Got: %d", len(tc.lines), len(lines))
+			}
+			for i := range lines {
+				if lines[i] != tc.lines[i] {
+					t.Fatalf("Expected: %q \t Got: %q", tc.lines[i], lines[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTo256(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		color [3]uint8
+		ret   int
+	}{
+		{name: "Black", color: [3]uint8{0x00, 0x00, 0x00}, ret: 16},
+		{name: "White", color: [3]uint8{0xFF, 0xFF, 0xFF}, ret: 231},
+		{name: "Red", color: [3]uint8{0xFF, 0x00, 0x00}, ret: 196},
+		{name: "Gray", color: [3]uint8{0x80, 0x80, 0x80}, ret: 244},
+		{name: "Cube", color: [3]uint8{0x5F, 0x87, 0xAF}, ret: 67},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if ret := to256(tc.color); ret != tc.ret {
+				t.Fatalf("Expected: %d \t Got: %d", tc.ret, ret)
+			}
+		})
+	}
+}
+
+func TestTo16(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		color [3]uint8
+		ret   int
+	}{
+		{name: "Black", color: [3]uint8{0x00, 0x00, 0x00}, ret: 0},
+		{name: "White", color: [3]uint8{0xFF, 0xFF, 0xFF}, ret: 15},
+		{name: "Dark red", color: [3]uint8{0x90, 0x10, 0x10}, ret: 1},
+		{name: "Light gray", color: [3]uint8{0xB0, 0xB0, 0xB0}, ret: 7},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if ret := to16(tc.color); ret != tc.ret {
+				t.Fatalf("Expected: %d \t Got: %d", tc.ret, ret)
+			}
+		})
+	}
+}
+
+func TestDetectColors(t *testing.T) {
+	tests := []struct {
+		name      string
+		colorterm string
+		term      string
+		ret       string
+	}{
+		{name: "truecolor", colorterm: "truecolor", term: "xterm", ret: "truecolor"},
+		{name: "24bit", colorterm: "24bit", term: "screen", ret: "truecolor"},
+		{name: "256 colors", term: "screen-256color", ret: "256"},
+		{name: "16 colors", term: "xterm", ret: "16"},
+		{name: "Dumb", term: "dumb", ret: "ascii"},
+		{name: "No terminal", ret: "ascii"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("COLORTERM", tc.colorterm)
+			t.Setenv("TERM", tc.term)
+			if ret := detectColors(); ret != tc.ret {
+				t.Fatalf("Expected: %s \t Got: %s", tc.ret, ret)
+			}
+		})
+	}
+}
@@ -0,0 +1,386 @@
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/gopacket"
+)
+
+// tuiHistory is the number of packets kept for scrolling back in interactive mode
+const tuiHistory = 1000
+
+// tuiPrefix is the number of characters in front of each packet in interactive mode
+const tuiPrefix = 8
+
+// validBits holds the supported numbers of bits per pixel in ascending order
+var validBits = []uint{1, 3, 6, 9, 12, 15, 18, 21, 24}
+
+// logicGates holds the logical operations in the order they are cycled through
+var logicGates = []string{"none", "xor", "or", "and", "not", "nand"}
+
+// key represents an action in interactive mode
+type key int
+
+const (
+	keyQuit key = iota
+	keyPause
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyDetails
+	keyMoreBits
+	keyLessBits
+	keyGate
+)
+
+// parseKeys translates the input of the terminal into actions
+func parseKeys(in []byte) []key {
+	var keys []key
+
+	for i := 0; i < len(in); i++ {
+		switch in[i] {
+		case 'q', 0x03:
+			keys = append(keys, keyQuit)
+		case ' ', 'p':
+			keys = append(keys, keyPause)
+		case 'k':
+			keys = append(keys, keyUp)
+		case 'j':
+			keys = append(keys, keyDown)
+		case '\r', '\n':
+			keys = append(keys, keyDetails)
+		case '+':
+			keys = append(keys, keyMoreBits)
+		case '-':
+			keys = append(keys, keyLessBits)
+		case 'l':
+			keys = append(keys, keyGate)
+		case 0x1B:
+			// Escape sequences of the cursor keys
+			if i+2 >= len(in) || in[i+1] != '[' {
+				continue
+			}
+			switch in[i+2] {
+			case 'A':
+				keys = append(keys, keyUp)
+				i += 2
+			case 'B':
+				keys = append(keys, keyDown)
+				i += 2
+			case '5', '6':
+				if i+3 < len(in) && in[i+3] == '~' {
+					if in[i+2] == '5' {
+						keys = append(keys, keyPageUp)
+					} else {
+						keys = append(keys, keyPageDown)
+					}
+					i += 3
+				}
+			}
+		}
+	}
+	return keys
+}
+
+// tui holds the state of the interactive mode
+type tui struct {
+	cfg     configs
+	ring    []data // Most recent packets
+	start   int    // Index of the oldest packet within ring
+	total   uint   // Number of received packets
+	sel     uint   // Number of the selected packet
+	top     uint   // Number of the first packet on the screen
+	paused  bool
+	details bool
+	done    bool // Source is exhausted
+}
+
+func newTUI(cfg configs) *tui {
+	return &tui{cfg: cfg}
+}
+
+// add stores pkt and selects it unless the view is paused
+func (t *tui) add(pkt data) {
+	if len(t.ring) < tuiHistory {
+		t.ring = append(t.ring, pkt)
+	} else {
+		t.ring[t.start] = pkt
+		t.start = (t.start + 1) % tuiHistory
+	}
+	t.total++
+	if !t.paused {
+		t.sel = t.total
+	}
+}
+
+// first returns the number of the oldest packet still available
+func (t *tui) first() uint {
+	return t.total - uint(len(t.ring)) + 1
+}
+
+// packet returns the packet with number num
+func (t *tui) packet(num uint) data {
+	return t.ring[(t.start+int(num-t.first()))%len(t.ring)]
+}
+
+func (t *tui) move(lines int) {
+	t.paused = true
+	if len(t.ring) == 0 {
+		return
+	}
+	sel := int(t.sel) + lines
+	if sel < int(t.first()) {
+		sel = int(t.first())
+	} else if sel > int(t.total) {
+		sel = int(t.total)
+	}
+	t.sel = uint(sel)
+}
+
+// handleKey changes the state according to k and reports whether to quit
+func (t *tui) handleKey(k key, lines int) bool {
+	switch k {
+	case keyQuit:
+		return true
+	case keyPause:
+		t.paused = !t.paused
+		if !t.paused {
+			t.sel = t.total
+		}
+	case keyUp:
+		t.move(-1)
+	case keyDown:
+		t.move(1)
+	case keyPageUp:
+		t.move(-lines)
+	case keyPageDown:
+		t.move(lines)
+	case keyDetails:
+		t.details = !t.details
+	case keyMoreBits, keyLessBits:
+		for i, bits := range validBits {
+			if bits != t.cfg.bpP {
+				continue
+			}
+			if k == keyMoreBits && i+1 < len(validBits) {
+				t.cfg.bpP = validBits[i+1]
+			} else if k == keyLessBits && i > 0 {
+				t.cfg.bpP = validBits[i-1]
+			}
+			break
+		}
+	case keyGate:
+		for i, name := range logicGates {
+			if name == t.cfg.logicOp.name {
+				value := t.cfg.logicOp.value
+				t.cfg.logicOp = getLogicGate(logicGates[(i+1)%len(logicGates)])
+				t.cfg.logicOp.value = value
+				break
+			}
+		}
+	}
+	return false
+}
+
+// decodeLayers returns a description of each protocol layer of pkt
+func decodeLayers(pkt data) []string {
+	var descs []string
+
+	payload := pkt.payload[:pkt.len]
+	packet := gopacket.NewPacket(payload, getDecoder(pkt.layer, pkt.linkType, payload), gopacket.Default)
+	for _, l := range packet.Layers() {
+		if l.LayerType() == gopacket.LayerTypeDecodeFailure {
+			continue
+		}
+		desc := l.LayerType().String()
+		switch layer := l.(type) {
+		case gopacket.LinkLayer:
+			desc += " " + layer.LinkFlow().String()
+		case gopacket.NetworkLayer:
+			desc += " " + layer.NetworkFlow().String()
+		case gopacket.TransportLayer:
+			desc += " " + layer.TransportFlow().String()
+		}
+		descs = append(descs, fmt.Sprintf("%s (%d bytes)", desc, len(l.LayerContents())))
+	}
+	if err := packet.ErrorLayer(); err != nil {
+		descs = append(descs, fmt.Sprintf("could not decode: %s", err.Error()))
+	}
+	return descs
+}
+
+// clip shortens s to width characters
+func clip(s string, width int) string {
+	if width < 0 {
+		return ""
+	}
+	if r := []rune(s); len(r) > width {
+		return string(r[:width])
+	}
+	return s
+}
+
+// draw returns the screen for a terminal of the given size
+func (t *tui) draw(width, height int) []byte {
+	var screen bytes.Buffer
+	var lines []string
+
+	state := "LIVE"
+	if t.done {
+		state = "DONE"
+	} else if t.paused {
+		state = "PAUSED"
+	}
+	status := fmt.Sprintf(" goNetViz %s | %d packets | %d bits | %s 0x%02X | q quit, space pause, up/down select, enter details, +/- bits, l gate",
+		state, t.total, t.cfg.bpP, t.cfg.logicOp.name, t.cfg.logicOp.value)
+	lines = append(lines, "\x1B[7m"+clip(status, width)+"\x1B[m")
+
+	list := height - 1
+	detail := 0
+	if t.details {
+		detail = height / 2
+		list -= detail
+	}
+
+	if len(t.ring) != 0 {
+		if t.sel < t.first() {
+			t.sel = t.first()
+		}
+		// Keep the selected packet on the screen and follow new packets unless paused
+		top := int(t.top)
+		if !t.paused || top < int(t.first()) || top > int(t.sel) {
+			top = int(t.sel) - list + 1
+		} else if int(t.sel) >= top+list {
+			top = int(t.sel) - list + 1
+		}
+		if top < int(t.first()) {
+			top = int(t.first())
+		}
+		t.top = uint(top)
+
+		r := newTerminalRenderer(nil, terminalOpts{width: width - tuiPrefix, colors: t.cfg.colors})
+		r.Begin(Image{Width: rowWidth(t.cfg)})
+		for num := t.top; num <= t.total && len(lines) <= list; num++ {
+			var line bytes.Buffer
+			marker := ' '
+			if num == t.sel {
+				marker = '>'
+			}
+			fmt.Fprintf(&line, "%c%6d ", marker, num)
+
+			pkt := t.packet(num)
+			payload := make([]byte, pkt.len)
+			copy(payload, pkt.payload)
+			pkt.payload = t.cfg.logicOp.gate(payload, t.cfg.logicOp.value)
+			row := packetToRow(pkt, 0, t.cfg)
+			colors := r.downscale(&row)
+			for x := 0; x < len(colors) && x < width-tuiPrefix; x++ {
+				line.WriteString(r.palette(colors[x], colors[x]))
+			}
+			line.WriteString(r.reset)
+			lines = append(lines, line.String())
+		}
+	}
+	for len(lines) <= list {
+		lines = append(lines, "")
+	}
+
+	if t.details && len(t.ring) != 0 {
+		pkt := t.packet(t.sel)
+		toa := time.Unix(0, pkt.toa).UTC().Format(time.RFC3339Nano)
+		lines = append(lines, "\x1B[7m"+clip(fmt.Sprintf(" Packet %d | %d of %d bytes | %s", t.sel, pkt.len, pkt.olen, toa), width)+"\x1B[m")
+		details := decodeLayers(pkt)
+		details = append(details, strings.Split(strings.TrimSuffix(hex.Dump(pkt.payload[:pkt.len]), "\n"), "\n")...)
+		for _, d := range details {
+			if len(lines) >= height {
+				break
+			}
+			lines = append(lines, clip(d, width))
+		}
+	}
+	for len(lines) < height {
+		lines = append(lines, "")
+	}
+
+	// Raw mode requires an explicit carriage return
+	screen.WriteString("\x1B[H")
+	screen.WriteString(strings.Join(lines[:height], "\x1B[K\r\n"))
+	screen.WriteString("\x1B[K")
+	return screen.Bytes()
+}
+
+// runInteractive shows the packets of ch in a full-screen view on the terminal
+// until it is left by the user or ctx is canceled. It returns the number of
+// received packets.
+func runInteractive(ctx context.Context, ch <-chan data, cfg configs) (uint, error) {
+	restore, err := makeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return 0, fmt.Errorf("could not enter interactive mode: %s", err.Error())
+	}
+	defer restore()
+
+	// Switch to the alternate screen and hide the cursor
+	fmt.Fprint(os.Stdout, "\x1B[?1049h\x1B[?25l")
+	defer fmt.Fprint(os.Stdout, "\x1B[?25h\x1B[?1049l")
+
+	keys := make(chan []byte)
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			in := make([]byte, n)
+			copy(in, buf[:n])
+			select {
+			case keys <- in:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+
+	ui := newTUI(cfg)
+	dirty := true
+	for {
+		select {
+		case <-ctx.Done():
+			return ui.total, nil
+		case pkt, ok := <-ch:
+			if !ok {
+				ch = nil
+				ui.done = true
+			} else {
+				ui.add(pkt)
+			}
+			dirty = true
+		case in := <-keys:
+			_, height := terminalSize()
+			for _, k := range parseKeys(in) {
+				if ui.handleKey(k, height/2) {
+					return ui.total, nil
+				}
+			}
+			dirty = true
+		case <-ticker.C:
+			if !dirty {
+				continue
+			}
+			if _, err := os.Stdout.Write(ui.draw(terminalSize())); err != nil {
+				return ui.total, fmt.Errorf("could not write to terminal: %s", err.Error())
+			}
+			dirty = false
+		}
+	}
+}
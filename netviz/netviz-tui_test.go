@@ -0,0 +1,196 @@
+package netviz
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		in   []byte
+		keys []key
+	}{
+		{name: "Quit", in: []byte("q"), keys: []key{keyQuit}},
+		{name: "Ctrl-C", in: []byte{0x03}, keys: []key{keyQuit}},
+		{name: "Cursor keys", in: []byte("\x1B[A\x1B[B"), keys: []key{keyUp, keyDown}},
+		{name: "Page keys", in: []byte("\x1B[5~\x1B[6~"), keys: []key{keyPageUp, keyPageDown}},
+		{name: "Multiple keys", in: []byte(" \r+-l"), keys: []key{keyPause, keyDetails, keyMoreBits, keyLessBits, keyGate}},
+		{name: "Unknown keys", in: []byte("x\x1B[Z\x1B"), keys: nil},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			keys := parseKeys(tc.in)
+			if len(keys) != len(tc.keys) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.keys, keys)
+			}
+			for i := range keys {
+				if keys[i] != tc.keys[i] {
+					t.Fatalf("Expected: %v \t Got: %v", tc.keys, keys)
+				}
+			}
+		})
+	}
+}
+
+func TestTUIRing(t *testing.T) {
+	t.Parallel()
+
+	ui := newTUI(configs{bpP: 24, xlimit: 1500})
+	for i := 0; i < tuiHistory+10; i++ {
+		ui.add(data{toa: int64(i + 1), len: 1, payload: []byte{byte(i)}})
+	}
+
+	if ui.total != tuiHistory+10 {
+		t.Fatalf("Expected %d packets \t Got: %d", tuiHistory+10, ui.total)
+	}
+	if ui.first() != 11 {
+		t.Fatalf("Expected 11 as oldest packet \t Got: %d", ui.first())
+	}
+	for _, num := range []uint{11, 500, tuiHistory + 10} {
+		if pkt := ui.packet(num); pkt.toa != int64(num) {
+			t.Fatalf("Expected packet %d \t Got: %d", num, pkt.toa)
+		}
+	}
+	if ui.sel != ui.total {
+		t.Fatalf("Expected packet %d to be selected \t Got: %d", ui.total, ui.sel)
+	}
+}
+
+func TestTUIHandleKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		bits   uint
+		gate   string
+		keys   []key
+		quit   bool
+		sel    uint
+		paused bool
+		ret    uint
+		retOp  string
+	}{
+		{name: "Quit", bits: 24, gate: "none", keys: []key{keyQuit}, quit: true, sel: 5, ret: 24, retOp: "none"},
+		{name: "Pause", bits: 24, gate: "none", keys: []key{keyPause}, sel: 5, paused: true, ret: 24, retOp: "none"},
+		{name: "Resume", bits: 24, gate: "none", keys: []key{keyUp, keyPause}, sel: 5, ret: 24, retOp: "none"},
+		{name: "Scroll", bits: 24, gate: "none", keys: []key{keyUp, keyUp, keyDown}, sel: 4, paused: true, ret: 24, retOp: "none"},
+		{name: "Page up", bits: 24, gate: "none", keys: []key{keyPageUp}, sel: 1, paused: true, ret: 24, retOp: "none"},
+		{name: "Page down", bits: 24, gate: "none", keys: []key{keyPageUp, keyPageDown}, sel: 5, paused: true, ret: 24, retOp: "none"},
+		{name: "More bits", bits: 12, gate: "none", keys: []key{keyMoreBits}, sel: 5, ret: 15, retOp: "none"},
+		{name: "Maximum bits", bits: 24, gate: "none", keys: []key{keyMoreBits}, sel: 5, ret: 24, retOp: "none"},
+		{name: "Minimum bits", bits: 1, gate: "none", keys: []key{keyLessBits, keyLessBits}, sel: 5, ret: 1, retOp: "none"},
+		{name: "Gate", bits: 24, gate: "none", keys: []key{keyGate, keyGate}, sel: 5, ret: 24, retOp: "or"},
+		{name: "Last gate", bits: 24, gate: "nand", keys: []key{keyGate}, sel: 5, ret: 24, retOp: "none"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := configs{bpP: tc.bits, xlimit: 1500, logicOp: getLogicGate(tc.gate)}
+			cfg.logicOp.value = 0xFF
+			ui := newTUI(cfg)
+			for i := 0; i < 5; i++ {
+				ui.add(data{len: 1, payload: []byte{byte(i)}})
+			}
+			var quit bool
+			for _, k := range tc.keys {
+				quit = ui.handleKey(k, 10)
+			}
+			if quit != tc.quit {
+				t.Fatalf("Expected quit: %t \t Got: %t", tc.quit, quit)
+			}
+			if ui.sel != tc.sel || ui.paused != tc.paused {
+				t.Fatalf("Expected: %d/%t \t Got: %d/%t", tc.sel, tc.paused, ui.sel, ui.paused)
+			}
+			if ui.cfg.bpP != tc.ret {
+				t.Fatalf("Expected %d bits \t Got: %d", tc.ret, ui.cfg.bpP)
+			}
+			if ui.cfg.logicOp.name != tc.retOp || ui.cfg.logicOp.value != 0xFF {
+				t.Fatalf("Expected %s 0xFF \t Got: %s 0x%X", tc.retOp, ui.cfg.logicOp.name, ui.cfg.logicOp.value)
+			}
+		})
+	}
+}
+
+func TestDecodeLayers(t *testing.T) {
+	t.Parallel()
+
+	buf := gopacket.NewSerializeBuffer()
+	if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true},
+		&layers.Ethernet{SrcMAC: []byte{0xc0, 0x25, 0x06, 0x44, 0xfc, 0x43}, DstMAC: []byte{0x5c, 0xe0, 0xc5, 0x8a, 0xa1, 0x79}, EthernetType: layers.EthernetTypeIPv4},
+		&layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP, SrcIP: []byte{192, 168, 178, 45}, DstIP: []byte{8, 8, 8, 8}},
+		&layers.UDP{SrcPort: 4242, DstPort: 4243},
+		gopacket.Payload{0xCA, 0xFE}); err != nil {
+		t.Fatalf("Could not serialize packet: %v", err)
+	}
+	packet := buf.Bytes()
+
+	tests := []struct {
+		name   string
+		pkt    data
+		layers []string
+	}{
+		{name: "Link layer", pkt: data{len: len(packet), linkType: layers.LinkTypeEthernet, payload: packet}, layers: []string{"Ethernet c0:25:06:44:fc:43->5c:e0:c5:8a:a1:79 (14 bytes)", "IPv4 192.168.178.45->8.8.8.8 (20 bytes)", "UDP 4242->4243 (8 bytes)", "Payload (2 bytes)"}},
+		{name: "Network layer", pkt: data{len: len(packet) - 14, layer: 1, payload: packet[14:]}, layers: []string{"IPv4 192.168.178.45->8.8.8.8 (20 bytes)", "UDP 4242->4243 (8 bytes)", "Payload (2 bytes)"}},
+		{name: "Payload", pkt: data{len: 2, layer: 3, payload: packet[len(packet)-2:]}, layers: []string{"Payload (2 bytes)"}},
+		{name: "Truncated", pkt: data{len: 10, linkType: layers.LinkTypeEthernet, payload: packet[:10]}, layers: []string{"could not decode: Ethernet packet too small"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			descs := decodeLayers(tc.pkt)
+			if strings.Join(descs, "|") != strings.Join(tc.layers, "|") {
+				t.Fatalf("Expected: %v \t Got: %v", tc.layers, descs)
+			}
+		})
+	}
+}
+
+func TestTUIDraw(t *testing.T) {
+	t.Parallel()
+
+	cfg := configs{bpP: 24, xlimit: 1500, logicOp: getLogicGate("none"), terminalOpts: terminalOpts{colors: "ascii"}}
+
+	tests := []struct {
+		name    string
+		packets int
+		keys    []key
+		height  int
+		lines   map[int]string
+	}{
+		{name: "Empty", packets: 0, height: 5, lines: map[int]string{1: "\x1B[K\r"}},
+		{name: "Follow", packets: 10, height: 5, lines: map[int]string{1: "      7 ", 4: ">    10 "}},
+		{name: "Scrolled", packets: 10, keys: []key{keyPageUp, keyPageUp}, height: 5, lines: map[int]string{1: ">     1 ", 4: "      4 "}},
+		{name: "Details", packets: 10, keys: []key{keyDetails}, height: 8, lines: map[int]string{3: ">    10 ", 4: "\x1B[7m Packet 10 ", 5: "Payload (1 bytes)", 6: "00000000  09"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ui := newTUI(cfg)
+			for i := 0; i < tc.packets; i++ {
+				ui.add(data{len: 1, olen: 1, layer: 3, payload: []byte{byte(i)}})
+			}
+			for _, k := range tc.keys {
+				ui.handleKey(k, 10)
+			}
+			lines := strings.Split(string(ui.draw(40, tc.height)), "\n")
+			if len(lines) != tc.height {
+				t.Fatalf("Expected %d lines \t Got: %d", tc.height, len(lines))
+			}
+			if !strings.HasPrefix(lines[0], "\x1B[H\x1B[7m goNetViz") {
+				t.Fatalf("Expected status line \t Got: %q", lines[0])
+			}
+			for i, prefix := range tc.lines {
+				if !strings.HasPrefix(lines[i], prefix) {
+					t.Fatalf("Expected line %d to start with %q \t Got: %q", i, prefix, lines[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package netviz
+
+import (
+	"context"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// settings collects the options before they are checked
+type settings struct {
+	cfg     configs
+	console bool
+	rebuild bool
+	lGate   string
+	lValue  string
+}
+
+// Option configures a Visualizer or Reconstructor
+type Option func(*settings)
+
+// WithInput sets the file or network interface to read from
+func WithInput(input string) Option {
+	return func(s *settings) {
+		s.cfg.input = input
+	}
+}
+
+// WithPcap opens the input with pcap
+func WithPcap() Option {
+	return func(s *settings) {
+		s.cfg.flags |= usePcap
+	}
+}
+
+// WithFilter sets a BPF filter for the input
+func WithFilter(filter string) Option {
+	return func(s *settings) {
+		s.cfg.filter = filter
+	}
+}
+
+// WithCount sets the number of packets to process. 0 removes the limit.
+func WithCount(count uint) Option {
+	return func(s *settings) {
+		s.cfg.limit = count
+	}
+}
+
+// WithPacketsPerImage sets the number of packets per image. 0 removes the limit.
+func WithPacketsPerImage(packets uint) Option {
+	return func(s *settings) {
+		s.cfg.ppI = packets
+	}
+}
+
+// WithBitsPerPixel sets the number of bits per pixel. It must be divisible
+// by three and smaller than 25 or 1.
+func WithBitsPerPixel(bits uint) Option {
+	return func(s *settings) {
+		s.cfg.bpP = bits
+	}
+}
+
+// WithTimeslize creates one image per duration instead of a fixed number of packets
+func WithTimeslize(duration time.Duration) Option {
+	return func(s *settings) {
+		s.cfg.ts = int64(duration)
+	}
+}
+
+// WithRowResolution sets the duration covered by each row with timeslize.
+// Packets of an already occupied row are stacked onto the following rows.
+func WithRowResolution(duration time.Duration) Option {
+	return func(s *settings) {
+		s.cfg.rowRes = int64(duration)
+	}
+}
+
+// WithRowGap shortens runs of empty rows with timeslize to gap rows. 0 keeps all of them.
+// The default is 10.
+func WithRowGap(gap uint) Option {
+	return func(s *settings) {
+		s.cfg.rowGap = gap
+	}
+}
+
+// WithTerminal visualizes the packets on the terminal
+func WithTerminal() Option {
+	return func(s *settings) {
+		s.console = true
+	}
+}
+
+// WithInteractive browses the packets in a full-screen view on the terminal
+func WithInteractive() Option {
+	return func(s *settings) {
+		s.cfg.flags |= interactive
+	}
+}
+
+// WithScale sets the scaling factor of the images
+func WithScale(scale uint) Option {
+	return func(s *settings) {
+		s.cfg.scale = scale
+	}
+}
+
+// WithLimit sets the maximum number of bytes per packet
+func WithLimit(limit uint) Option {
+	return func(s *settings) {
+		s.cfg.xlimit = limit
+	}
+}
+
+// WithPrefix sets the prefix of the resulting files
+func WithPrefix(prefix string) Option {
+	return func(s *settings) {
+		s.cfg.prefix = prefix
+	}
+}
+
+// WithOutput sets the template for the names of the resulting files without
+// extension. Supported placeholders are {index}, {start}, {end}, {iface},
+// {filter} and {format}. Missing directories are created.
+func WithOutput(template string) Option {
+	return func(s *settings) {
+		s.cfg.output = template
+	}
+}
+
+// WithOverwrite replaces existing files instead of failing
+func WithOverwrite() Option {
+	return func(s *settings) {
+		s.cfg.flags |= overwrite
+	}
+}
+
+// WithFormat sets the format of the resulting images
+func WithFormat(format string) Option {
+	return func(s *settings) {
+		s.cfg.format = format
+	}
+}
+
+// WithRenderer passes the images to r instead of writing them in the
+// configured format. The images are passed to r one after another.
+func WithRenderer(r Renderer) Option {
+	return func(s *settings) {
+		s.cfg.renderer = r
+	}
+}
+
+// WithCompact merges pixels of the same color within a packet into a single element
+func WithCompact() Option {
+	return func(s *settings) {
+		s.cfg.flags |= compact
+	}
+}
+
+// WithAxes adds a byte offset ruler, the packet index and timestamp of each row
+// and the hex of each packet as tooltip to svg images
+func WithAxes() Option {
+	return func(s *settings) {
+		s.cfg.flags |= axes
+	}
+}
+
+// WithLayer sets the first protocol layer to visualize
+func WithLayer(layer string) Option {
+	return func(s *settings) {
+		s.cfg.layer = layer
+	}
+}
+
+// WithLayerTint tints the link, network and transport layer header in different colors
+func WithLayerTint() Option {
+	return func(s *settings) {
+		s.cfg.flags |= layerTint
+	}
+}
+
+// WithLogicGate applies the logical operation gate with operand value to each packet
+func WithLogicGate(gate, value string) Option {
+	return func(s *settings) {
+		s.lGate = gate
+		s.lValue = value
+	}
+}
+
+// WithPromisc puts the network interface into promiscuous mode
+func WithPromisc(promisc bool) Option {
+	return func(s *settings) {
+		s.cfg.promisc = promisc
+	}
+}
+
+// WithTimeout sets the read timeout of the network interface
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *settings) {
+		s.cfg.timeout = timeout
+	}
+}
+
+// WithBufferSize sets the size of the capture buffer in bytes
+func WithBufferSize(size int) Option {
+	return func(s *settings) {
+		s.cfg.bufSize = size
+	}
+}
+
+// WithImmediate delivers packets of the network interface as soon as they arrive
+func WithImmediate(immediate bool) Option {
+	return func(s *settings) {
+		s.cfg.immediate = immediate
+	}
+}
+
+// WithTerminalWidth sets the number of characters per line on the terminal.
+// 0 detects the width of the terminal.
+func WithTerminalWidth(width uint) Option {
+	return func(s *settings) {
+		s.cfg.width = int(width)
+	}
+}
+
+// WithTerminalWrap wraps packets that don't fit into the width of the
+// terminal instead of downscaling them
+func WithTerminalWrap() Option {
+	return func(s *settings) {
+		s.cfg.wrap = true
+	}
+}
+
+// WithTerminalColors sets the palette for output on the terminal. Supported
+// palettes are auto, truecolor, 256, 16 and ascii.
+func WithTerminalColors(colors string) Option {
+	return func(s *settings) {
+		s.cfg.colors = colors
+	}
+}
+
+// WithFraming splits raw input into records instead of chunks of the limit.
+// Supported are fixed:<bytes>, delim:<separator> and len:<1|2be|2le|4be|4le>.
+func WithFraming(framing string) Option {
+	return func(s *settings) {
+		s.cfg.framing = framing
+	}
+}
+
+func newSettings(rebuild bool, opts []Option) (configs, error) {
+	s := settings{
+		cfg: configs{
+			bpP:    24,
+			ppI:    25,
+			scale:  1,
+			xlimit: 1500,
+			rowGap: 10,
+			prefix: "image",
+			format: "svg",
+			layer:  "link",
+			captureOpts: captureOpts{
+				promisc: true,
+				timeout: 500 * time.Millisecond,
+			},
+		},
+		rebuild: rebuild,
+		lValue:  "0xFF",
+	}
+
+	for _, opt := range opts {
+		opt(&s)
+	}
+
+	if err := checkConfig(&s.cfg, s.console, s.rebuild, s.lGate, s.lValue); err != nil {
+		return configs{}, err
+	}
+	return s.cfg, nil
+}
+
+// Visualizer turns network packets into images
+type Visualizer struct {
+	cfg configs
+}
+
+// New returns a Visualizer configured by opts
+func New(opts ...Option) (*Visualizer, error) {
+	cfg, err := newSettings(false, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Visualizer{cfg: cfg}, nil
+}
+
+// Run visualizes the packets of the configured input until it is exhausted
+// or ctx is canceled. On cancellation the packets read so far are flushed
+// into images and an *InterruptedError is returned.
+func (v *Visualizer) Run(ctx context.Context) error {
+	return run(ctx, v.cfg)
+}
+
+// Visualize visualizes the packets of src until it is exhausted or ctx is canceled.
+// On cancellation the packets read so far are flushed into images and an
+// *InterruptedError is returned. src is not closed by Visualize.
+func (v *Visualizer) Visualize(ctx context.Context, src Source) error {
+	g, ctx := errgroup.WithContext(ctx)
+	return visualizeSource(ctx, g, src, v.cfg)
+}
+
+// Reconstructor turns images of a Visualizer back into a pcap
+type Reconstructor struct {
+	cfg configs
+}
+
+// NewReconstructor returns a Reconstructor configured by opts
+func NewReconstructor(opts ...Option) (*Reconstructor, error) {
+	cfg, err := newSettings(true, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Reconstructor{cfg: cfg}, nil
+}
+
+// Run creates a pcap from the configured input. No capture is written
+// if ctx is canceled before all packets have been extracted.
+func (r *Reconstructor) Run(ctx context.Context) error {
+	return run(ctx, r.cfg)
+}
@@ -0,0 +1,347 @@
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/pcapgo"
+)
+
+// sliceSource provides packets from a slice
+type sliceSource struct {
+	packets []Packet
+}
+
+func (s *sliceSource) Read(ctx context.Context, limit uint) (Packet, error) {
+	if len(s.packets) == 0 {
+		return Packet{}, io.EOF
+	}
+	pkt := s.packets[0]
+	s.packets = s.packets[1:]
+	return pkt, nil
+}
+
+func (s *sliceSource) Close() error {
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		err  string
+	}{
+		{name: "Defaults", opts: []Option{WithInput("input")}},
+		{name: "Options", opts: []Option{WithInput("input"), WithBitsPerPixel(3), WithPacketsPerImage(2), WithCount(10), WithScale(2), WithLimit(64), WithFormat("png"), WithLayer("network"), WithLayerTint(), WithLogicGate("xor", "0x42")}},
+		{name: "Invalid bits", opts: []Option{WithBitsPerPixel(2)}, err: "-bits 2 is not divisible by three or one"},
+		{name: "Timeslize", opts: []Option{WithInput("input"), WithTimeslize(time.Second), WithRowResolution(10 * time.Microsecond), WithRowGap(5)}},
+		{name: "Output", opts: []Option{WithInput("input"), WithOutput("out/{iface}-{index}"), WithOverwrite()}},
+		{name: "Framing", opts: []Option{WithInput("input"), WithFraming("len:2le")}},
+		{name: "Unknown placeholder", opts: []Option{WithInput("input"), WithOutput("{date}")}, err: "-output {date} contains the unknown placeholder {date}"},
+		{name: "Terminal and Timeslize", opts: []Option{WithTerminal(), WithTimeslize(time.Second)}, err: "-timeslize and -terminal can't be combined"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := New(tc.opts...)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+		})
+	}
+}
+
+func TestVisualizerVisualize(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestVisualizerVisualize")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	v, err := New(WithPrefix(fmt.Sprintf("%s/image", dir)), WithPacketsPerImage(2))
+	if err != nil {
+		t.Fatalf("Could not create visualizer: %v", err)
+	}
+
+	src := &sliceSource{packets: []Packet{
+		{Timestamp: time.Unix(1257894000, 0), Data: []byte{0xCA, 0xFE}},
+		{Timestamp: time.Unix(1257894001, 0), Data: []byte{0xC0, 0xFF, 0xEE}, OrigLength: 1500},
+		{Data: []byte{0x42}},
+	}}
+	if err := v.Visualize(context.Background(), src); err != nil {
+		t.Fatalf("Could not visualize: %v", err)
+	}
+
+	images, _ := filepath.Glob(fmt.Sprintf("%s/image-*.svg", dir))
+	if len(images) != 2 {
+		t.Fatalf("Expected 2 images, got: %v", images)
+	}
+}
+
+func TestNewReconstructor(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		err  string
+	}{
+		{name: "Input", opts: []Option{WithInput("input.svg")}},
+		{name: "Without input", err: "-file is needed as source"},
+		{name: "Terminal", opts: []Option{WithInput("input.svg"), WithTerminal()}, err: "-terminal and -reverse can't be combined"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewReconstructor(tc.opts...)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestRoundTrip")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	payload := []byte{0x45, 0x00, 0x00, 0x14, 0xCA, 0xFE, 0xC0, 0xFF, 0xEE, 0x2E, 0x3E, 0x5D, 0x7C, 0x55, 0x74, 0x01, 0x80, 0x7F, 0xFE, 0x42}
+
+	// 20 pixels hold 2.5 bytes with one and 7.5 bytes with three bits per pixel
+	tests := []struct {
+		name    string
+		opts    []Option
+		format  string
+		content []byte
+	}{
+		{name: "1 bit svg", opts: []Option{WithBitsPerPixel(1)}, format: "pcap", content: payload[:2]},
+		{name: "1 bit png", opts: []Option{WithBitsPerPixel(1), WithFormat("png")}, format: "pcapng", content: payload[:2]},
+		{name: "3 bits svg", opts: []Option{WithBitsPerPixel(3)}, format: "pcapng", content: payload[:7]},
+		{name: "3 bits compact", opts: []Option{WithBitsPerPixel(3), WithCompact()}, format: "pcap", content: payload[:7]},
+		{name: "3 bits png", opts: []Option{WithBitsPerPixel(3), WithFormat("png")}, format: "pcap", content: payload[:7]},
+		{name: "24 bits", opts: []Option{WithBitsPerPixel(24)}, format: "pcap", content: payload},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prefix := fmt.Sprintf("%s/%d", dir, i)
+			opts := append(tc.opts, WithPrefix(prefix+"-image"), WithLimit(20), WithLogicGate("xor", "0x5A"))
+			v, err := New(opts...)
+			if err != nil {
+				t.Fatalf("Could not create visualizer: %v", err)
+			}
+			src := &sliceSource{packets: []Packet{{Timestamp: time.Unix(1257894000, 0), Data: payload}}}
+			if err := v.Visualize(context.Background(), src); err != nil {
+				t.Fatalf("Could not visualize: %v", err)
+			}
+
+			images, _ := filepath.Glob(prefix + "-image-*")
+			if len(images) != 1 {
+				t.Fatalf("Expected 1 image, got: %v", images)
+			}
+			r, err := NewReconstructor(WithInput(images[0]), WithPrefix(prefix), WithFormat(tc.format))
+			if err != nil {
+				t.Fatalf("Could not create reconstructor: %v", err)
+			}
+			if err := r.Run(context.Background()); err != nil {
+				t.Fatalf("Could not reconstruct: %v", err)
+			}
+
+			f, err := os.Open(fmt.Sprintf("%s.%s", prefix, tc.format))
+			if err != nil {
+				t.Fatalf("Could not open capture: %v", err)
+			}
+			defer f.Close()
+			var capture interface {
+				ReadPacketData() ([]byte, gopacket.CaptureInfo, error)
+			}
+			if tc.format == "pcapng" {
+				capture, err = pcapgo.NewNgReader(f, pcapgo.DefaultNgReaderOptions)
+			} else {
+				capture, err = pcapgo.NewReader(f)
+			}
+			if err != nil {
+				t.Fatalf("Could not read capture: %v", err)
+			}
+			content, _, err := capture.ReadPacketData()
+			if err != nil {
+				t.Fatalf("Could not read packet: %v", err)
+			}
+			if !bytes.Equal(content, tc.content) {
+				t.Fatalf("Expected: %x \t Got: %x", tc.content, content)
+			}
+		})
+	}
+}
+
+// cancelSource cancels the context after providing its packets
+type cancelSource struct {
+	sliceSource
+	cancel context.CancelFunc
+}
+
+func (s *cancelSource) Read(ctx context.Context, limit uint) (Packet, error) {
+	if len(s.packets) == 0 {
+		s.cancel()
+		<-ctx.Done()
+		return Packet{}, ctx.Err()
+	}
+	return s.sliceSource.Read(ctx, limit)
+}
+
+func TestVisualizerInterrupted(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestVisualizerInterrupted")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name     string
+		existing bool // The image to write exists already
+		err      string
+	}{
+		{name: "Partial image", err: "interrupted after 2 packets and 1 images"},
+		{name: "Existing image", existing: true, err: "file exists"},
+	}
+
+	for i, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			prefix := fmt.Sprintf("%s/%d", dir, i)
+			if tc.existing {
+				if err := ioutil.WriteFile(prefix+"-1.svg", nil, 0644); err != nil {
+					t.Fatalf("Could not write file: %v", err)
+				}
+			}
+			v, err := New(WithPrefix(prefix), WithPacketsPerImage(25))
+			if err != nil {
+				t.Fatalf("Could not create visualizer: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			src := &cancelSource{cancel: cancel, sliceSource: sliceSource{packets: []Packet{
+				{Timestamp: time.Unix(1257894000, 0), Data: []byte{0xCA, 0xFE}},
+				{Timestamp: time.Unix(1257894001, 0), Data: []byte{0xC0, 0xFF, 0xEE}},
+			}}}
+			err = v.Visualize(ctx, src)
+			if matched, _ := regexp.MatchString(tc.err, fmt.Sprint(err)); matched == false {
+				t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+			}
+			var interrupted *InterruptedError
+			if errors.As(err, &interrupted) == tc.existing {
+				t.Fatalf("Expected interrupted: %v \t Got: %v", !tc.existing, err)
+			}
+			if tc.existing {
+				return
+			}
+
+			// The packets read before the cancellation are flushed into an image
+			raw, err := ioutil.ReadFile(prefix + "-1.svg")
+			if err != nil {
+				t.Fatalf("Could not read image: %v", err)
+			}
+			if matched, _ := regexp.MatchString(`"packets":\[\{"index":1,.*\{"index":2,`, string(raw)); matched == false {
+				t.Fatalf("Expected both packets in the image")
+			}
+		})
+	}
+}
+
+// recordingRenderer records the images and rows passed to it
+type recordingRenderer struct {
+	images []Image
+	rows   [][]Row
+}
+
+func (r *recordingRenderer) Begin(img Image) error {
+	r.images = append(r.images, img)
+	r.rows = append(r.rows, nil)
+	return nil
+}
+
+func (r *recordingRenderer) AddRow(row Row) error {
+	r.rows[len(r.rows)-1] = append(r.rows[len(r.rows)-1], row)
+	return nil
+}
+
+func (r *recordingRenderer) End() error {
+	return nil
+}
+
+func TestWithRenderer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestWithRenderer")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	r := &recordingRenderer{}
+	v, err := New(WithPrefix(fmt.Sprintf("%s/image", dir)), WithPacketsPerImage(2), WithRenderer(r))
+	if err != nil {
+		t.Fatalf("Could not create visualizer: %v", err)
+	}
+
+	src := &sliceSource{packets: []Packet{
+		{Data: []byte{0xCA, 0xFE}},
+		{Data: []byte{0xC0, 0xFF, 0xEE}},
+		{Data: []byte{0x42}},
+	}}
+	if err := v.Visualize(context.Background(), src); err != nil {
+		t.Fatalf("Could not visualize: %v", err)
+	}
+
+	if len(r.images) != 2 || r.images[0].Name != fmt.Sprintf("%s/image-1", dir) || r.images[1].Name != fmt.Sprintf("%s/image-2", dir) {
+		t.Fatalf("Unexpected images: %v", r.images)
+	}
+	for i, expected := range [][]byte{{0xCA, 0xFE}, {0xC0, 0xFF, 0xEE}, {0x42}} {
+		if row := r.rows[i/2][i%2]; !bytes.Equal(row.Data, expected) {
+			t.Fatalf("Expected: %v \t Got: %v", expected, row.Data)
+		}
+	}
+	if files, _ := filepath.Glob(fmt.Sprintf("%s/*", dir)); len(files) != 0 {
+		t.Fatalf("Expected no files, got: %v", files)
+	}
+}
+
+func TestDefaultTimeslizeHeight(t *testing.T) {
+	cfg, err := newSettings(false, []Option{WithInput("input"), WithTimeslize(time.Second)})
+	if err != nil {
+		t.Fatalf("Could not create settings: %v", err)
+	}
+
+	// Sparse traffic spread over a time slice of one second
+	var content []data
+	for i := 0; i < 10; i++ {
+		content = append(content, data{toa: int64(i) * int64(100*time.Millisecond), len: 1, payload: []byte{0xCA}})
+	}
+	img, _ := createRows(content, 1, cfg)
+	if max := len(content) * int(cfg.rowGap+1); img.Height > max {
+		t.Fatalf("Expected a height of at most %d \t Got: %d", max, img.Height)
+	}
+}
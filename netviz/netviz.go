@@ -0,0 +1,1100 @@
+// Package netviz visualizes network traffic as images and reconstructs
+// network traffic from these images.
+package netviz
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	solder      = 0x01
+	terminal    = 0x02
+	timeslize   = 0x04
+	reverse     = 0x08
+	stilMask    = 0x0f
+	file        = 0x10
+	dev         = 0x20
+	usePcap     = 0x40
+	sourceMask  = 0x70
+	compact     = 0x80
+	layerTint   = 0x100
+	interactive = 0x200
+	axes        = 0x400
+	overwrite   = 0x800
+)
+
+// Version number of this tool
+const Version = "0.0.5"
+
+// Data is a struct for each network packet
+type data struct {
+	toa      int64           // Timestamp of arrival in nanoseconds
+	len      int             // Length of packet
+	olen     int             // Original length of packet
+	headers  []int           // End of the link, network and transport layer header
+	layer    int             // Protocol layer the payload starts with
+	num      uint            // Number of the packet within the source
+	linkType layers.LinkType // Link type of packets starting at the link layer
+	row      int             // Row of the packet within a reconstructed image
+	payload  []byte          // Copied network packet
+}
+
+// protocolLayers holds the names of the protocol layers a visualization can start with
+var protocolLayers = []string{"link", "network", "transport", "payload"}
+
+// layerTints holds the colors for the link, network and transport layer header
+var layerTints = [][3]uint8{{0x00, 0x00, 0xFF}, {0x00, 0xFF, 0x00}, {0xFF, 0x00, 0x00}}
+
+// logicOp represents the logical operation
+type logicOp struct {
+	name  string                                    // logical operation name
+	gate  func(payload []byte, operand byte) []byte // logical operation on the input bytes
+	value byte                                      // value for the logical operation
+}
+
+// captureOpts represents the options for capturing on a network interface
+type captureOpts struct {
+	snaplen   int           // Number of bytes to capture per packet
+	promisc   bool          // Put the interface into promiscuous mode
+	timeout   time.Duration // Read timeout of the interface
+	bufSize   int           // Size of the capture buffer in bytes
+	immediate bool          // Deliver packets as soon as they arrive
+}
+
+// terminalOpts represents the options for output on the terminal
+type terminalOpts struct {
+	width  int    // Number of characters per line, 0 detects the width of the terminal
+	wrap   bool   // Wrap rows instead of downscaling them
+	colors string // Palette for output on the terminal
+}
+
+// configs represents all the configuration data
+type configs struct {
+	bpP      uint            // Bits per Pixel
+	ppI      uint            // Number of packets per Image
+	ts       int64           // Nanoseconds per image with timeslize
+	rowRes   int64           // Nanoseconds per row with timeslize
+	rowGap   uint            // Maximum number of empty rows with timeslize, 0 keeps all of them
+	limit    uint            // Number of network packets to process
+	flags    uint            // Type of illustration
+	scale    uint            // Scaling factor for output
+	xlimit   uint            // Limit of bytes per packet
+	filter   string          // filter for the network interface
+	input    string          // source of data
+	prefix   string          // prefix for the visualization results
+	output   string          // template for the names of the visualization results
+	format   string          // format of the visualization results
+	layer    string          // first protocol layer of the visualization results
+	linkType layers.LinkType // link type of the source
+	renderer Renderer        // output backend that replaces the format
+	logicOp
+	captureOpts
+	terminalOpts
+	framingOpts
+}
+
+// Packet represents a single network packet provided by a Source
+type Packet struct {
+	Timestamp  time.Time // Time of arrival
+	Length     int       // Number of captured bytes, defaults to the length of Data
+	OrigLength int       // Original length of the packet, defaults to Length
+	Headers    []int     // End of the link, network and transport layer header within Data
+	Layer      int       // Protocol layer Data starts with
+	Data       []byte    // Content of the packet
+}
+
+// Source provides network packets for the visualization
+type Source interface {
+	// Read returns the next packet. limit is the maximum number of bytes
+	// that will be visualized per packet.
+	Read(ctx context.Context, limit uint) (Packet, error)
+	Close() error
+}
+
+// LinkTypeSource is a Source, whose packets don't necessarily start with an
+// Ethernet header. Packets of other sources are expected to be Ethernet.
+type LinkTypeSource interface {
+	Source
+	// LinkType returns the link type of the packets
+	LinkType() layers.LinkType
+}
+
+// InterruptedError is returned, if the context is canceled before the source
+// is exhausted. The packets read until then are flushed into Images images.
+type InterruptedError struct {
+	Packets uint  // Number of packets read from the source
+	Images  uint  // Number of images created
+	Err     error // Error of the canceled context
+}
+
+func (e *InterruptedError) Error() string {
+	return fmt.Sprintf("interrupted after %d packets and %d images: %s", e.Packets, e.Images, e.Err.Error())
+}
+
+func (e *InterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// getSourceLinkType returns the link type of the packets of src
+func getSourceLinkType(src Source) layers.LinkType {
+	if l, ok := src.(LinkTypeSource); ok {
+		return l.LinkType()
+	}
+	return layers.LinkTypeEthernet
+}
+
+// newData converts pkt into its internal representation padded to limit bytes
+func newData(pkt Packet, limit uint) data {
+	var toa int64
+	if !pkt.Timestamp.IsZero() {
+		toa = pkt.Timestamp.UnixNano()
+	}
+	length := pkt.Length
+	if length == 0 || length > len(pkt.Data) {
+		length = len(pkt.Data)
+	}
+	olen := pkt.OrigLength
+	if olen < length {
+		olen = length
+	}
+	payload := make([]byte, int(limit))
+	copy(payload, pkt.Data)
+	if length > int(limit) {
+		length = int(limit)
+	}
+	return data{toa: toa, len: length, olen: olen, headers: pkt.Headers, layer: pkt.Layer, payload: payload}
+}
+
+// stdinInput is the input that reads from standard input
+const stdinInput = "-"
+
+type pcapInput struct {
+	closer   io.Closer
+	filter   packetFilter // Filter for packets of offline captures
+	source   *gopacket.PacketSource
+	linkType layers.LinkType
+	layer    int
+}
+
+func (p pcapInput) LinkType() layers.LinkType {
+	return p.linkType
+}
+
+func (p pcapInput) Read(ctx context.Context, limit uint) (Packet, error) {
+	var packet gopacket.Packet
+	var err error
+	src := p.source
+	// The read timeout gives the chance to check for cancellation while waiting for packets
+	for {
+		if ctx.Err() != nil {
+			return Packet{}, ctx.Err()
+		}
+		packet, err = src.NextPacket()
+		if err == nil && p.filter != nil && !p.filter(packet) {
+			continue
+		}
+		if !timeoutExpired(err) {
+			break
+		}
+	}
+	if err != nil {
+		return Packet{}, err
+	}
+	info := packet.Metadata().CaptureInfo
+	headers := getHeaders(packet)
+	var offset int
+	if p.layer > 0 {
+		offset = headers[p.layer-1]
+	}
+	for i := range headers {
+		headers[i] -= offset
+		if headers[i] < 0 {
+			headers[i] = 0
+		}
+	}
+	content := packet.Data()[offset:]
+	return Packet{Timestamp: info.Timestamp, Length: len(content), OrigLength: info.Length - offset, Headers: headers, Layer: p.layer, Data: content}, nil
+}
+
+// getLayer returns the index of the protocol layer name
+func getLayer(name string) (int, error) {
+	if len(name) == 0 {
+		return 0, nil
+	}
+	for i, layer := range protocolLayers {
+		if strings.ToLower(name) == layer {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("unrecognized layer: %s", name)
+}
+
+// getHeaders returns the offsets where the link, network and transport layer header of packet end
+func getHeaders(packet gopacket.Packet) []int {
+	var offset int
+	headers := make([]int, len(layerTints))
+
+	for _, layer := range packet.Layers() {
+		offset += len(layer.LayerContents())
+		switch layer.(type) {
+		case gopacket.LinkLayer:
+			headers[0] = offset
+		case gopacket.NetworkLayer:
+			headers[1] = offset
+		case gopacket.TransportLayer:
+			headers[2] = offset
+		}
+	}
+
+	// Missing layers don't cover any bytes
+	for i := 1; i < len(headers); i++ {
+		if headers[i] < headers[i-1] {
+			headers[i] = headers[i-1]
+		}
+	}
+	return headers
+}
+
+// getDecoder returns the decoder for payload starting at the protocol layer with index layer
+func getDecoder(layer int, linkType layers.LinkType, payload []byte) gopacket.Decoder {
+	switch layer {
+	case 0:
+		return linkType
+	case 1:
+		if len(payload) > 0 && payload[0]>>4 == 6 {
+			return layers.LayerTypeIPv6
+		}
+		return layers.LayerTypeIPv4
+	default:
+		return gopacket.LayerTypePayload
+	}
+}
+
+func (p pcapInput) Close() error {
+	return p.closer.Close()
+}
+
+func getBitsFromPacket(packet []byte, byteP, bitP *int, bpP uint) uint8 {
+	var c uint8
+	for i := 0; i < (int(bpP) / 3); i++ {
+		if *byteP >= len(packet) {
+			break
+		}
+		c |= (packet[*byteP] & (1 << uint8(7-*bitP)))
+		*bitP++
+		if *bitP%8 == 0 {
+			*bitP = 0
+			*byteP++
+		}
+	}
+	return c
+}
+
+func createPixel(packet []byte, byteP, bitP *int, bpP uint) (uint8, uint8, uint8) {
+	var r, g, b uint8
+
+	if bpP == 1 {
+		if (packet[*byteP] & (1 << uint8(7-*bitP))) == 0 {
+			r, g, b = uint8(0), uint8(0), uint8(0)
+		} else {
+			r, g, b = uint8(255), uint8(255), uint8(255)
+		}
+		*bitP++
+		if *bitP%8 == 0 {
+			*bitP = 0
+			*byteP++
+		}
+	} else {
+		r = getBitsFromPacket(packet, byteP, bitP, bpP)
+		g = getBitsFromPacket(packet, byteP, bitP, bpP)
+		b = getBitsFromPacket(packet, byteP, bitP, bpP)
+	}
+
+	return r, g, b
+}
+
+// tintPixel blends a pixel with the color of the layer header the byte at offset belongs to
+func tintPixel(r, g, b uint8, offset int, headers []int) (uint8, uint8, uint8) {
+	for i, end := range headers {
+		if offset >= end {
+			continue
+		}
+		tint := layerTints[i]
+		return uint8((int(r) + int(tint[0])) / 2), uint8((int(g) + int(tint[1])) / 2), uint8((int(b) + int(tint[2])) / 2)
+	}
+	return r, g, b
+}
+
+// createFile creates filename and its missing directories. Existing files
+// are only replaced, if overwrite is set.
+func createFile(filename string, cfg configs) (*os.File, error) {
+	if dir := filepath.Dir(filename); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("could not create directory %s: %s", dir, err.Error())
+		}
+	}
+
+	mode := os.O_RDWR | os.O_CREATE | os.O_EXCL
+	if (cfg.flags & overwrite) == overwrite {
+		mode = os.O_RDWR | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(filename, mode, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("could not open file %s: %s", filename, err.Error())
+	}
+	return f, nil
+}
+
+func createImage(filename string, width, height int, content string, rows []Row, cfg configs) error {
+	if len(content) == 0 {
+		return fmt.Errorf("no content to write")
+	}
+
+	meta, err := newMetadata(cfg, rows).encode()
+	if err != nil {
+		return err
+	}
+
+	f, err := createFile(filename, cfg)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.WriteString(fmt.Sprintf("<?xml version=\"1.0\"?>\n<svg width=\"%d\" height=\"%d\">\n", width, height)); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write header: %s", err.Error())
+	}
+
+	if _, err := f.WriteString(fmt.Sprintf("<metadata id=\"goNetViz\">%s</metadata>\n", meta)); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write metadata: %s", err.Error())
+	}
+
+	var source = cfg.input
+
+	if _, err := f.WriteString(fmt.Sprintf("<!--\n\tgoNetViz \"%s\"\n\tScale=%d\n\tBitsPerPixel=%d\n\tDTG=\"%s\"\n\tSource=\"%s\"\n\tFilter=\"%s\"\n\tLogicGate=\"%s\"\n\tLogicValue=0x%X\n\tLayerTint=%t\n\tLayer=\"%s\"\n-->\n",
+		Version, cfg.scale, cfg.bpP, time.Now().UTC(), source, cfg.filter, cfg.logicOp.name, cfg.logicOp.value, (cfg.flags&layerTint) == layerTint, cfg.layer)); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write additional information: %s", err.Error())
+	}
+
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write content: %s", err.Error())
+	}
+
+	if _, err := f.WriteString("</svg>"); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write closing information: %s", err.Error())
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("could not close file %s: %s", filename, err.Error())
+	}
+	return nil
+}
+
+// createRow returns the row of pkt. Only the bytePos bytes that are completely
+// covered by pixels count as captured, as the bits of a partially covered byte
+// can't be reconstructed.
+func createRow(pkt data, pixels []Pixel, bytePos int) Row {
+	caplen := pkt.len
+	if caplen > len(pkt.payload) {
+		caplen = len(pkt.payload)
+	}
+	if caplen > bytePos {
+		caplen = bytePos
+	}
+	olen := pkt.olen
+	if olen < caplen {
+		olen = caplen
+	}
+	return Row{Index: pkt.num, Arrival: pkt.toa, CapLen: caplen, Len: olen, Data: pkt.payload[:caplen], Pixels: pixels}
+}
+
+// packetToRow turns the payload of a packet into the pixels of row y
+func packetToRow(pkt data, y int, cfg configs) Row {
+	var xPos int
+	var bitPos int
+	var bytePos int
+	var pixels []Pixel
+	var packetLen = len(pkt.payload)
+
+	for {
+		offset := bytePos
+		r, g, b := createPixel(pkt.payload, &bytePos, &bitPos, uint(cfg.bpP))
+		r, g, b = tintPixel(r, g, b, offset, pkt.headers)
+		pixels = append(pixels, Pixel{X: xPos, Y: y, R: r, G: g, B: b})
+		xPos++
+		if bytePos >= packetLen {
+			break
+		}
+		if xPos >= int(cfg.xlimit) && cfg.xlimit != 0 {
+			break
+		}
+	}
+	return createRow(pkt, pixels, bytePos)
+}
+
+// createRows turns the packets of content into the rows of a single image
+func createRows(content []data, num uint, cfg configs) (Image, []Row) {
+	var yPos = -1
+	var firstPkg time.Time
+	var rows []Row
+	var width int
+	var skipped int64
+	var rowRes = cfg.rowRes
+
+	if rowRes <= 0 {
+		rowRes = int64(time.Microsecond)
+	}
+
+	for pkg := range content {
+		if firstPkg.IsZero() {
+			firstPkg = time.Unix(0, content[pkg].toa)
+		}
+		if (cfg.flags & stilMask) == solder {
+			yPos++
+		} else {
+			current := (content[pkg].toa - content[0].toa) / rowRes
+			// Long runs of empty rows are shortened to rowGap rows. Rows of
+			// stacked packets are occupied and don't count as empty.
+			if gap := current - (int64(yPos) + skipped) - 1; cfg.rowGap != 0 && gap > int64(cfg.rowGap) {
+				skipped += gap - int64(cfg.rowGap)
+			}
+			// Packets of an already occupied row are stacked onto the following rows
+			if y := int(current - skipped); y > yPos {
+				yPos = y
+			} else {
+				yPos++
+			}
+		}
+		r := packetToRow(content[pkg], yPos, cfg)
+		if len(r.Pixels) > width {
+			width = len(r.Pixels)
+		}
+		rows = append(rows, r)
+	}
+
+	var last int64
+	if len(content) > 0 {
+		last = content[len(content)-1].toa
+	}
+	filename := getFilename(cfg, num, firstPkg, time.Unix(0, last))
+
+	return Image{Name: filename, Width: width, Height: yPos + 1}, rows
+}
+
+// placeholders holds the supported placeholders of the output template
+var placeholders = []string{"{index}", "{start}", "{end}", "{iface}", "{filter}", "{format}"}
+
+// filenameTime is the layout of timestamps within filenames without colons
+const filenameTime = "20060102T150405.000000Z"
+
+// sanitize replaces all characters of s that might not be supported within filenames
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// getFilename returns the name of an image without extension. first and last
+// are the arrival of the first and the last packet within the image.
+func getFilename(cfg configs, num uint, first, last time.Time) string {
+	if len(cfg.output) == 0 {
+		if (cfg.flags & stilMask) == timeslize {
+			return cfg.prefix + "-" + first.Format(time.RFC3339Nano)
+		}
+		return cfg.prefix + "-" + fmt.Sprint(num)
+	}
+
+	return strings.NewReplacer(
+		"{index}", fmt.Sprint(num),
+		"{start}", first.UTC().Format(filenameTime),
+		"{end}", last.UTC().Format(filenameTime),
+		"{iface}", sanitize(filepath.Base(cfg.input)),
+		"{filter}", sanitize(cfg.filter),
+		"{format}", cfg.format,
+	).Replace(cfg.output)
+}
+
+// uniqueNames reports whether the output template of cfg gives each image a
+// name of its own or whether only a single image is created
+func uniqueNames(cfg configs) bool {
+	switch cfg.flags & stilMask {
+	case terminal, reverse:
+		return true
+	case timeslize:
+		return cfg.format == "gif" || cfg.format == "apng" ||
+			strings.Contains(cfg.output, "{index}") || strings.Contains(cfg.output, "{start}")
+	}
+	if cfg.ppI == 0 || (cfg.limit != 0 && cfg.limit <= cfg.ppI) {
+		return true
+	}
+	return strings.Contains(cfg.output, "{index}")
+}
+
+func createVisualization(g *errgroup.Group, content []data, num uint, cfg configs) {
+	img, rows := createRows(content, num, cfg)
+	if cfg.renderer != nil {
+		// A renderer of the user isn't expected to handle images concurrently
+		err := render(cfg.renderer, img, rows)
+		g.Go(func() error {
+			return err
+		})
+		return
+	}
+	g.Go(func() error {
+		return render(newRenderer(cfg), img, rows)
+	})
+}
+
+func handlePackets(ctx context.Context, g *errgroup.Group, input Source, cfg configs, ch chan<- data) error {
+	var count uint
+	var num = cfg.limit
+	var limit = cfg.xlimit
+	var logicValue = cfg.logicOp.value
+	var logicGate = cfg.logicOp.gate
+
+	defer close(ch)
+
+	for {
+		p, err := input.Read(ctx, limit)
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("could not read from %s: %s", cfg.input, err.Error())
+		}
+		count++
+		if num != 0 && count > num {
+			return nil
+		}
+
+		pkt := newData(p, limit)
+		if len(pkt.payload) == 0 || pkt.len == 0 {
+			continue
+		}
+
+		if (cfg.flags & layerTint) == 0 {
+			pkt.headers = nil
+		}
+
+		pkt.num = count
+		pkt.linkType = cfg.linkType
+		pkt.payload = logicGate(pkt.payload, logicValue)
+		ch <- pkt
+	}
+}
+
+// getSnaplen returns the number of bytes to capture per packet
+func getSnaplen(xlimit uint, layer string) int {
+	// Headers in front of the visualized layer have to be captured as well
+	if xlimit == 0 || (len(layer) != 0 && layer != protocolLayers[0]) {
+		return 65535
+	}
+	return int(xlimit)
+}
+
+func initPcapSource(input, filter string, device bool, layer string, capture captureOpts) (Source, error) {
+	l, err := getLayer(layer)
+	if err != nil {
+		return nil, err
+	}
+
+	if device {
+		return initLiveSource(input, filter, l, capture)
+	}
+
+	f, err := os.Open(input)
+	if err != nil {
+		return nil, fmt.Errorf("could not open file %s: %s", input, err.Error())
+	}
+	return initOfflineSource(f, input, filter, l)
+}
+
+func initSource(ctx context.Context, input, filter string, pcap bool, layer string, capture captureOpts, framing framingOpts) (handle Source, err error) {
+	var device bool
+
+	if input == stdinInput {
+		l, err := getLayer(layer)
+		if err != nil {
+			return nil, err
+		}
+		return initStreamSource(ctx, ioutil.NopCloser(os.Stdin), "stdin", filter, pcap, l, framing)
+	}
+
+	if _, err := net.InterfaceByName(input); err == nil {
+		device = true
+	}
+
+	if len(filter) > 0 || pcap {
+		return initPcapSource(input, filter, device, layer, capture)
+	}
+
+	if device {
+		return nil, fmt.Errorf("please open networking interface with pcap support")
+	}
+
+	fi, err := os.Lstat(input)
+	if err != nil {
+		return nil, fmt.Errorf("could not get file information")
+	}
+	mode := fi.Mode()
+
+	switch {
+	case mode.IsDir():
+		return nil, fmt.Errorf(fmt.Sprintf("Can not handle %s as source", input))
+	case mode&os.ModeNamedPipe != 0:
+		l, err := getLayer(layer)
+		if err != nil {
+			return nil, err
+		}
+		f, err := os.Open(input)
+		if err != nil {
+			return nil, fmt.Errorf("could not open file %s: %s", input, err.Error())
+		}
+		return initStreamSource(ctx, f, input, filter, pcap, l, framing)
+	case mode.IsRegular():
+		fallthrough
+	case mode&os.ModeCharDevice == 0:
+		fallthrough
+	case mode&os.ModeSocket == 0:
+		f, err := os.Open(input)
+		if err != nil {
+			return nil, fmt.Errorf("could not open file %s: %s", input, err.Error())
+		}
+		handle = newRegularFile(f, framing)
+	default:
+		return nil, fmt.Errorf(fmt.Sprintf("Can not handle %s as source", input))
+	}
+	return
+}
+
+func getOperand(val string) (byte, error) {
+	var i int
+	var j int64
+	var err error
+
+	smallVal := strings.ToLower(val)
+
+	if strings.HasPrefix(smallVal, "0x") || strings.ContainsAny(smallVal, "abcdef") {
+		j, err = strconv.ParseInt(strings.TrimPrefix(strings.ToLower(val), "0x"), 16, 16)
+		i = int(j)
+	} else {
+		i, err = strconv.Atoi(val)
+	}
+
+	if err != nil {
+		return 0x00, fmt.Errorf("could not convert %s", val)
+	}
+
+	if i < 0 || i > 255 {
+		return 0x0, fmt.Errorf("%s is not a valid value", val)
+	}
+
+	return byte(i), nil
+}
+
+func opXor(payload []byte, operand byte) []byte {
+	for i := range payload {
+		payload[i] ^= operand
+	}
+	return payload
+}
+
+func opOr(payload []byte, operand byte) []byte {
+	for i := range payload {
+		payload[i] |= operand
+	}
+	return payload
+}
+
+func opAnd(payload []byte, operand byte) []byte {
+	for i := range payload {
+		payload[i] &= operand
+	}
+	return payload
+}
+
+func opNot(payload []byte, operand byte) []byte {
+	for i := range payload {
+		payload[i] = ^(payload[i])
+	}
+	return payload
+}
+
+func opNand(payload []byte, operand byte) []byte {
+	for i := range payload {
+		payload[i] &^= operand
+	}
+	return payload
+}
+
+func opDefault(payload []byte, operand byte) []byte {
+	return payload
+}
+
+// getLogicGate returns the logical operation called name
+func getLogicGate(name string) logicOp {
+	switch strings.ToLower(name) {
+	case "xor":
+		return logicOp{name: "xor", gate: opXor}
+	case "or":
+		return logicOp{name: "or", gate: opOr}
+	case "and":
+		return logicOp{name: "and", gate: opAnd}
+	case "not":
+		return logicOp{name: "not", gate: opNot}
+	case "nand":
+		return logicOp{name: "nand", gate: opNand}
+	default:
+		return logicOp{name: "none", gate: opDefault}
+	}
+}
+
+func checkConfig(cfg *configs, console, rebuild bool, lGate string, lValue string) error {
+	var err error
+
+	cfg.logicOp = getLogicGate(lGate)
+	cfg.logicOp.value, err = getOperand(lValue)
+	if err != nil {
+		return err
+	}
+
+	if console || (cfg.flags&interactive) == interactive {
+		cfg.flags |= terminal
+	}
+
+	if rebuild {
+		cfg.flags |= reverse
+	}
+
+	if cfg.bpP%3 != 0 && cfg.bpP != 1 {
+		return fmt.Errorf("-bits %d is not divisible by three or one", cfg.bpP)
+	} else if cfg.bpP > 25 {
+		return fmt.Errorf("-bits %d must be smaller than 25", cfg.bpP)
+	}
+
+	if cfg.ts > 0 {
+		cfg.flags |= timeslize
+	}
+
+	for _, p := range regexp.MustCompile(`\{[^{}]*\}`).FindAllString(cfg.output, -1) {
+		var known bool
+		for _, placeholder := range placeholders {
+			known = known || p == placeholder
+		}
+		if !known {
+			return fmt.Errorf("-output %s contains the unknown placeholder %s", cfg.output, p)
+		}
+	}
+
+	if cfg.rowRes < 0 {
+		return fmt.Errorf("-rowres has to be positive")
+	} else if cfg.rowRes == 0 {
+		cfg.rowRes = int64(time.Microsecond)
+	}
+
+	switch strings.ToLower(cfg.format) {
+	case "", "svg":
+		cfg.format = "svg"
+	case "png":
+		cfg.format = "png"
+	case "html":
+		cfg.format = "html"
+	case "gif", "apng":
+		cfg.format = strings.ToLower(cfg.format)
+		if (cfg.flags & timeslize) == 0 {
+			return fmt.Errorf("-format %s works only with -timeslize", cfg.format)
+		}
+	case "pcap", "pcapng":
+		cfg.format = strings.ToLower(cfg.format)
+		if (cfg.flags & reverse) == 0 {
+			return fmt.Errorf("-format %s works only with -reverse", cfg.format)
+		}
+	case "terminal":
+		cfg.format = "terminal"
+		cfg.flags |= terminal
+	default:
+		return fmt.Errorf("-format %s is not supported", cfg.format)
+	}
+
+	// Output on the terminal replaces the format, before options for the format are checked
+	if (cfg.flags & terminal) == terminal {
+		cfg.format = "terminal"
+	}
+
+	if (cfg.flags&compact) == compact && cfg.format != "svg" {
+		return fmt.Errorf("-compact works only with svg as format")
+	}
+
+	if (cfg.flags&axes) == axes && cfg.format != "svg" {
+		return fmt.Errorf("-axes works only with svg as format")
+	}
+
+	switch colors := strings.ToLower(cfg.colors); colors {
+	case "", "auto":
+		cfg.colors = "auto"
+	default:
+		if _, ok := palettes[colors]; !ok {
+			return fmt.Errorf("-colors %s is not supported", cfg.colors)
+		}
+		cfg.colors = colors
+	}
+
+	layer, err := getLayer(cfg.layer)
+	if err != nil {
+		return fmt.Errorf("-layer %s is not supported", cfg.layer)
+	}
+	cfg.layer = protocolLayers[layer]
+
+	if (cfg.flags&layerTint) == layerTint && rebuild {
+		return fmt.Errorf("-layerTint and -reverse can't be combined")
+	}
+
+	switch stil := (cfg.flags & stilMask); stil {
+	case (timeslize | terminal):
+		return fmt.Errorf("-timeslize and -terminal can't be combined")
+	case (timeslize | reverse):
+		return fmt.Errorf("-timeslize and -reverse can't be combined")
+	case (terminal | reverse):
+		return fmt.Errorf("-terminal and -reverse can't be combined")
+	case (terminal | timeslize | reverse):
+		return fmt.Errorf("-terminal, -timeslize and -reverse can't be combined")
+	case 0: /*	no specific option was given	*/
+		cfg.flags |= solder
+	}
+
+	if len(cfg.output) != 0 && !uniqueNames(*cfg) {
+		return fmt.Errorf("-output %s gives all images the same name, add {index} or with -timeslize {start}", cfg.output)
+	}
+
+	if (cfg.flags&stilMask) == reverse && (len(cfg.input) == 0 || (cfg.flags&sourceMask) == usePcap) {
+		return fmt.Errorf("-file is needed as source")
+	}
+
+	if cfg.input == stdinInput {
+		if (cfg.flags & interactive) == interactive {
+			return fmt.Errorf("-interactive uses stdin for keys and can't read packets from it")
+		}
+		if (cfg.flags & stilMask) == reverse {
+			return fmt.Errorf("-reverse can't read images from stdin")
+		}
+	}
+
+	cfg.framingOpts, err = getFraming(cfg.framing)
+	if err != nil {
+		return err
+	}
+	if len(cfg.framing) != 0 && (len(cfg.filter) != 0 || (cfg.flags&sourceMask) == usePcap || (cfg.flags&stilMask) == reverse) {
+		return fmt.Errorf("-framing works only for raw input")
+	}
+
+	if (cfg.flags&stilMask) == terminal && cfg.scale != 1 {
+		return fmt.Errorf("-scale and -terminal can't be combined")
+	}
+
+	if cfg.scale == 0 {
+		return fmt.Errorf("scale factor has to be at least 1")
+	}
+
+	if cfg.xlimit > 9000 {
+		return fmt.Errorf("limit has to be smallerthan a Jumbo frame (9000 bytes)")
+	}
+	cfg.snaplen = getSnaplen(cfg.xlimit, cfg.layer)
+
+	// Waiting for packets of a network interface can only be canceled after the read timeout
+	if cfg.timeout <= 0 {
+		return fmt.Errorf("-timeout has to be positive")
+	}
+
+	if (cfg.flags & stilMask) == terminal {
+		cfg.immediate = true
+	}
+
+	return nil
+}
+
+func visualize(ctx context.Context, g *errgroup.Group, cfg configs) error {
+	var err error
+	var handle Source
+
+	if (cfg.flags & sourceMask) == usePcap {
+		handle, err = initSource(ctx, cfg.input, cfg.filter, true, cfg.layer, cfg.captureOpts, cfg.framingOpts)
+	} else {
+		handle, err = initSource(ctx, cfg.input, cfg.filter, false, cfg.layer, cfg.captureOpts, cfg.framingOpts)
+	}
+	if err != nil {
+		return err
+	}
+	defer handle.Close()
+
+	return visualizeSource(ctx, g, handle, cfg)
+}
+
+// rowWidth returns the number of pixels of the longest possible packet
+func rowWidth(cfg configs) int {
+	width := int(cfg.xlimit)
+	if bits := int(cfg.bpP); bits > 0 && (width*8+bits-1)/bits < width {
+		width = (width*8 + bits - 1) / bits
+	}
+	return width
+}
+
+func visualizeSource(ctx context.Context, g *errgroup.Group, handle Source, cfg configs) error {
+	ch := make(chan data)
+	var content []data
+	var packets, images uint
+	var index uint = 1
+	var slicer int64
+	var err error
+	var anim *animationRenderer
+
+	cfg.linkType = getSourceLinkType(handle)
+
+	// Leaving the interactive mode stops reading from the source
+	qctx, quit := context.WithCancel(ctx)
+	defer quit()
+
+	source := cfg
+	if (cfg.flags & interactive) == interactive {
+		// The logical operation is applied by the interactive mode as it can be changed on the fly
+		source.logicOp = getLogicGate("none")
+	}
+
+	g.Go(func() error {
+		return handlePackets(qctx, g, handle, source, ch)
+	})
+
+	// Time slices of an animation are collected as frames of a single file
+	if cfg.renderer == nil && (cfg.format == "gif" || cfg.format == "apng") {
+		anim = newAnimationRenderer(cfg)
+	}
+	flush := func(content []data, num uint) {
+		if anim == nil {
+			createVisualization(g, content, num, cfg)
+			return
+		}
+		img, rows := createRows(content, num, cfg)
+		if rerr := render(anim, img, rows); rerr != nil && err == nil {
+			err = rerr
+		}
+	}
+
+	switch stil := (cfg.flags & stilMask); stil {
+	case solder:
+		for i, ok := <-ch; ok; i, ok = <-ch {
+			packets++
+			content = append(content, i)
+			if len(content) >= int(cfg.ppI) && cfg.ppI != 0 {
+				flush(content, index)
+				images++
+				index++
+				content = content[:0]
+			}
+		}
+	case terminal:
+		if (cfg.flags & interactive) == interactive {
+			packets, err = runInteractive(qctx, ch, cfg)
+			quit()
+			for range ch {
+			}
+			break
+		}
+		r := newRenderer(cfg)
+		err = r.Begin(Image{Width: rowWidth(cfg)})
+		for i, ok := <-ch; ok; i, ok = <-ch {
+			if err != nil {
+				continue
+			}
+			// Only the captured bytes of a packet are shown on the terminal
+			i.payload = i.payload[:i.len]
+			err = r.AddRow(packetToRow(i, int(packets), cfg))
+			packets++
+		}
+		if err == nil {
+			err = r.End()
+		}
+	case timeslize:
+		for i, ok := <-ch; ok; i, ok = <-ch {
+			packets++
+			if slicer == 0 {
+				slicer = i.toa + int64(cfg.ts)
+			}
+			if slicer < i.toa {
+				flush(content, index)
+				images++
+				index++
+				content = content[:0]
+				slicer = i.toa + int64(cfg.ts)
+			}
+			content = append(content, i)
+		}
+	}
+
+	// Flush the remaining packets, even if processing was interrupted
+	if len(content) > 0 {
+		flush(content, index)
+		images++
+	}
+	if anim != nil && err == nil {
+		err = anim.Close()
+	}
+
+	// The context is also canceled by errors of the group, which are returned instead
+	interrupted := ctx.Err()
+
+	if werr := g.Wait(); werr != nil {
+		return werr
+	}
+	if err == nil && interrupted != nil {
+		return &InterruptedError{Packets: packets, Images: images, Err: interrupted}
+	}
+	return err
+}
+
+func createBytes(slice []int, bitsPerByte int) []byte {
+	var bytes []byte
+	var tmp uint8
+	var shift int
+
+	for i, j := range slice {
+		for k := 0; k < bitsPerByte; k++ {
+			tmp |= (uint8(j) & (1 << uint8(7-shift%8)))
+			shift = shift + 1
+			if shift%8 == 0 && i != 0 {
+				bytes = append(bytes, byte(tmp))
+				tmp = 0
+			}
+		}
+	}
+	return bytes
+}
+
+func run(ctx context.Context, cfg configs) error {
+	g, ctx := errgroup.WithContext(ctx)
+
+	if (cfg.flags & stilMask) == reverse {
+		return reconstruct(ctx, g, cfg)
+	}
+	return visualize(ctx, g, cfg)
+}
@@ -0,0 +1,1207 @@
+package netviz
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcapgo"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	notSvg         = `This is not a svg`
+	withoutComment = `<?xml version="1.0"?>
+<svg width="6" height="1">
+<rect x="0" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="1" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="2" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="3" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="4" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="5" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+</svg>`
+	validSvg003 = `<?xml version="1.0"?>
+<svg width="6" height="2">
+<!--
+	goNetViz "0.0.3"
+	Scale=1
+	BitsPerPixel=3
+	DTG="Thu Nov 09 18:57:00 CET 1989"
+-->
+<rect x="0" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="1" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="2" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="0" y="1" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="1" y="1" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="2" y="1" width="1" height="1" style="fill:rgb(0,0,0)" />
+</svg>`
+	validSvg004 = `<?xml version="1.0"?>
+<svg width="6" height="4">
+<!--
+	goNetViz "0.0.4"
+	Scale=1
+	BitsPerPixel=24
+	DTG="Wed Sep 07 16:05:00 CET 1949"
+	Source="Bonn/Germany"
+	Filter="none"
+	LogicGate="none"
+	LogicValue=0xFF
+
+-->
+<rect x="0" y="0" width="1" height="1" style="fill:rgb(255,0,0)" />
+<rect x="1" y="0" width="1" height="1" style="fill:rgb(0,255,0)" />
+<rect x="2" y="0" width="1" height="1" style="fill:rgb(0,0,255)" />
+<rect x="0" y="1" width="1" height="1" style="fill:rgb(0,255,0)" />
+<rect x="1" y="1" width="1" height="1" style="fill:rgb(255,0,0)" />
+<rect x="2" y="1" width="1" height="1" style="fill:rgb(0,255,0)" />
+<rect x="0" y="2" width="1" height="1" style="fill:rgb(0,0,255)" />
+<rect x="1" y="2" width="1" height="1" style="fill:rgb(0,255,0)" />
+<rect x="2" y="2" width="1" height="1" style="fill:rgb(255,0,0)" />
+<rect x="0" y="3" width="1" height="1" style="fill:rgb(0,255,0)" />
+<rect x="1" y="3" width="1" height="1" style="fill:rgb(0,0,255)" />
+<rect x="2" y="3" width="1" height="1" style="fill:rgb(0,255,0)" />
+</svg>`
+	xorSvg004 = `<?xml version="1.0"?>
+<svg width="3" height="1">
+<!--
+	goNetViz "0.0.4"
+	Scale=1
+	BitsPerPixel=24
+	DTG="2019-09-07 16:05:00.123 +0000 UTC"
+	Source="/tmp/capture.pcap"
+	Filter="icmp and host 192.168.0.1"
+	LogicGate="xor"
+	LogicValue=0xF
+-->
+<rect x="0" y="0" width="1" height="1" style="fill:rgb(15,14,13)" />
+<rect x="1" y="0" width="1" height="1" style="fill:rgb(240,255,0)" />
+</svg>`
+	lossySvg004 = `<?xml version="1.0"?>
+<svg width="3" height="1">
+<!--
+	goNetViz "0.0.4"
+	Scale=1
+	BitsPerPixel=24
+	DTG="2019-09-07 16:05:00.123 +0000 UTC"
+	Source="eth0"
+	Filter=""
+	LogicGate="or"
+	LogicValue=0xF0
+-->
+<rect x="0" y="0" width="1" height="1" style="fill:rgb(240,240,240)" />
+</svg>`
+	validSvg005 = `<?xml version="1.0"?>
+<svg width="9" height="6">
+<!--
+	goNetViz "0.0.5"
+	Scale=3
+	BitsPerPixel=24
+	DTG="2019-09-07 16:05:00.123 +0000 UTC"
+	Source="eth0"
+	Filter=""
+	LogicGate="none"
+	LogicValue=0xFF
+-->
+<g data-toa="1257894000000000" data-caplen="7" data-len="1500">
+<rect x="0" y="0" width="3" height="3" style="fill:rgb(1,2,3)" />
+<rect x="3" y="0" width="3" height="3" style="fill:rgb(4,5,6)" />
+<rect x="6" y="0" width="3" height="3" style="fill:rgb(7,0,0)" />
+</g>
+<g data-toa="1257894000000001" data-caplen="2" data-len="2">
+<rect x="0" y="3" width="3" height="3" style="fill:rgb(202,254,0)" />
+</g>
+</svg>`
+	compactSvg005 = `<?xml version="1.0"?>
+<svg width="8" height="4">
+<!--
+	goNetViz "0.0.5"
+	Scale=2
+	BitsPerPixel=24
+	DTG="2019-09-07 16:05:00.123 +0000 UTC"
+	Source="eth0"
+	Filter=""
+	LogicGate="none"
+	LogicValue=0xFF
+-->
+<g data-toa="1257894000000000" data-caplen="12" data-len="12">
+<rect x="0" y="0" width="6" height="2" style="fill:rgb(0,0,0)" />
+<rect x="6" y="0" width="2" height="2" style="fill:rgb(1,2,3)" />
+</g>
+<g data-toa="1257894000000001" data-caplen="3" data-len="3">
+<rect x="0" y="2" width="2" height="2" style="fill:rgb(202,254,0)" />
+</g>
+</svg>`
+	tintedSvg005 = `<?xml version="1.0"?>
+<svg width="1" height="1">
+<!--
+	goNetViz "0.0.5"
+	Scale=1
+	BitsPerPixel=24
+	DTG="2019-09-07 16:05:00.123 +0000 UTC"
+	Source="eth0"
+	Filter=""
+	LogicGate="none"
+	LogicValue=0xFF
+	LayerTint=true
+-->
+<g data-toa="1257894000000000" data-caplen="3" data-len="3">
+<rect x="0" y="0" width="1" height="1" style="fill:rgb(101,102,231)" />
+</g>
+</svg>`
+	layerSvg005 = `<?xml version="1.0"?>
+<svg width="1" height="1">
+<!--
+	goNetViz "0.0.5"
+	Scale=1
+	BitsPerPixel=24
+	DTG="2019-09-07 16:05:00.123 +0000 UTC"
+	Source="eth0"
+	Filter=""
+	LogicGate="none"
+	LogicValue=0xFF
+	LayerTint=false
+	Layer="%s"
+-->
+<g data-toa="1257894000000000" data-caplen="3" data-len="3">
+<rect x="0" y="0" width="1" height="1" style="fill:rgb(69,0,0)" />
+</g>
+</svg>`
+	metadataSvg = `<?xml version="1.0"?>
+<svg width="2" height="2">
+<metadata id="goNetViz">{"version":"0.0.5","dtg":"2019-09-07 16:05:00.123 +0000 UTC","config":{"bitsPerPixel":24,"scale":2,"source":"eth0","layer":"link","layerTint":false,"logicGate":"xor","logicValue":255},"packets":[{"index":1,"row":0,"toa":1257894000000000,"caplen":3,"len":3}]}</metadata>
+<g data-toa="1257894000000000" data-caplen="3" data-len="3">
+<rect x="0" y="0" width="2" height="2" style="fill:rgb(254,253,252)" />
+</g>
+</svg>`
+	invalidVersion = `<?xml version="1.0"?>
+<svg width="6" height="2">
+<!--
+	goNetViz "0.0.0"
+	Scale=1
+	BitsPerPixel=3
+-->
+<rect x="0" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="1" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="2" y="0" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="0" y="1" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="1" y="1" width="1" height="1" style="fill:rgb(0,0,0)" />
+<rect x="2" y="1" width="1" height="1" style="fill:rgb(0,0,0)" />
+</svg>`
+
+	// pcapHeader = []byte{
+	//		0xa1, 0xb2, 0xc3, 0xd4, /*	Magic Number	*/
+	//		0x00, 0x02, /*	Major Number	*/
+	//		0x00, 0x04, /*	Minor Number	*/
+	//		0x00, 0x00, 0x00, 0x00, /*	GMT to Local	*/
+	//		0x00, 0x00, 0x00, 0x00, /*	Accuracy	*/
+	//		0x00, 0x00, 0x00, 0x00, /*	Max captured Length	*/
+	//		0x00, 0x00, 0x00, 0x01, /*	Data Link Type	*/
+	//	}
+
+	//	fakePacket = []byte{
+	//		0x00, 0x00, 0x00, 0x00, /* Timestamp in seconds	*/
+	//		0x00, 0x00, 0x00, 0x00, /* Timestamp in microseconds	*/
+	//		0x00, 0x00, 0x00, 0x00, /* Number of Octets	*/
+	//		0x00, 0x00, 0x00, 0x00, /* Actual Length	*/
+	//	}
+
+	fakeData = []byte{
+		0xa1, 0xb2, 0xc3, 0xd4, 0x00, 0x02, 0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x00, 0x00, 0xff, 0xff, 0x00, 0x00, 0x00, 0x01, 0x58, 0x30, 0x2d, 0x32, 0x00, 0x04, 0x63, 0x31,
+		0x00, 0x00, 0x01, 0xeb, 0x00, 0x00, 0x01, 0xeb, 0x7a, 0xca, 0x8f, 0xfa, 0xfc, 0x34, 0x74, 0x73,
+		0xa7, 0x4c, 0xe1, 0xef, 0x00, 0x45, 0x00, 0x08, 0xd8, 0x92, 0xdd, 0x01, 0x06, 0x40, 0x00, 0x40,
+		0xa8, 0xc0, 0xfa, 0x24, 0xa8, 0xc0, 0xfd, 0xff, 0x33, 0x88, 0xf9, 0xff, 0x1f, 0xe9, 0x48, 0x1f,
+		0x7a, 0x93, 0x41, 0x3f, 0x18, 0x80, 0x6d, 0xb7, 0xab, 0xac, 0x59, 0x05, 0x01, 0x01, 0x00, 0x00,
+		0x37, 0x01, 0x0a, 0x08, 0x00, 0x00, 0xa3, 0xad, 0x45, 0x47, 0x2a, 0x2d, 0x73, 0x2f, 0x20, 0x54,
+		0x70, 0x75, 0x74, 0x65, 0x72, 0x75, 0x65, 0x2f, 0x5f, 0x61, 0x6b, 0x65, 0x6f, 0x66, 0x6e, 0x69,
+		0x72, 0x61, 0x70, 0x3f, 0x3d, 0x73, 0x6d, 0x61, 0x73, 0x72, 0x65, 0x76, 0x2c, 0x6e, 0x6f, 0x69,
+		0x65, 0x6d, 0x61, 0x6e, 0x69, 0x75, 0x62, 0x2c, 0x69, 0x5f, 0x64, 0x6c, 0x2c, 0x6f, 0x66, 0x6e,
+		0x69, 0x76, 0x65, 0x64, 0x69, 0x5f, 0x65, 0x63, 0x2c, 0x6f, 0x66, 0x6e, 0x2c, 0x74, 0x65, 0x6e,
+		0x69, 0x66, 0x69, 0x77, 0x74, 0x65, 0x73, 0x2c, 0x73, 0x2c, 0x70, 0x75, 0x69, 0x74, 0x74, 0x65,
+		0x2c, 0x73, 0x67, 0x6e, 0x5f, 0x74, 0x70, 0x6f, 0x6f, 0x2c, 0x6e, 0x69, 0x63, 0x6e, 0x65, 0x70,
+		0x2c, 0x74, 0x73, 0x61, 0x74, 0x6c, 0x75, 0x6d, 0x6e, 0x6f, 0x7a, 0x69, 0x75, 0x61, 0x2c, 0x65,
+		0x2c, 0x6f, 0x69, 0x64, 0x6e, 0x67, 0x69, 0x73, 0x74, 0x65, 0x64, 0x2c, 0x26, 0x6c, 0x69, 0x61,
+		0x69, 0x74, 0x70, 0x6f, 0x3d, 0x73, 0x6e, 0x6f, 0x61, 0x74, 0x65, 0x64, 0x73, 0x2c, 0x6c, 0x69,
+		0x20, 0x6e, 0x67, 0x69, 0x50, 0x54, 0x54, 0x48, 0x31, 0x2e, 0x31, 0x2f, 0x72, 0x4f, 0x0a, 0x0d,
+		0x6e, 0x69, 0x67, 0x69, 0x74, 0x68, 0x20, 0x3a, 0x3a, 0x73, 0x70, 0x74, 0x77, 0x77, 0x2f, 0x2f,
+		0x6f, 0x67, 0x2e, 0x77, 0x65, 0x6c, 0x67, 0x6f, 0x6d, 0x6f, 0x63, 0x2e, 0x63, 0x41, 0x0a, 0x0d,
+		0x74, 0x70, 0x65, 0x63, 0x6e, 0x61, 0x4c, 0x2d, 0x67, 0x61, 0x75, 0x67, 0x65, 0x20, 0x3a, 0x65,
+		0x53, 0x55, 0x2d, 0x6e, 0x6e, 0x65, 0x20, 0x2c, 0x30, 0x3d, 0x71, 0x3b, 0x20, 0x2c, 0x38, 0x2e,
+		0x71, 0x3b, 0x6e, 0x65, 0x35, 0x2e, 0x30, 0x3d, 0x73, 0x55, 0x0a, 0x0d, 0x41, 0x2d, 0x72, 0x65,
+		0x74, 0x6e, 0x65, 0x67, 0x6f, 0x63, 0x20, 0x3a, 0x6f, 0x67, 0x2e, 0x6d, 0x65, 0x6c, 0x67, 0x6f,
+		0x64, 0x6e, 0x61, 0x2e, 0x64, 0x69, 0x6f, 0x72, 0x70, 0x70, 0x61, 0x2e, 0x68, 0x63, 0x2e, 0x73,
+		0x65, 0x6d, 0x6f, 0x72, 0x74, 0x73, 0x61, 0x63, 0x70, 0x70, 0x61, 0x2e, 0x31, 0x2e, 0x31, 0x2f,
+		0x39, 0x32, 0x2e, 0x39, 0x69, 0x4c, 0x28, 0x20, 0x3b, 0x78, 0x75, 0x6e, 0x20, 0x3b, 0x55, 0x20,
+		0x72, 0x64, 0x6e, 0x41, 0x20, 0x64, 0x69, 0x6f, 0x2e, 0x30, 0x2e, 0x36, 0x4e, 0x20, 0x3b, 0x31,
+		0x73, 0x75, 0x78, 0x65, 0x42, 0x20, 0x35, 0x20, 0x64, 0x6c, 0x69, 0x75, 0x42, 0x4f, 0x4d, 0x2f,
+		0x29, 0x5a, 0x30, 0x33, 0x6f, 0x48, 0x0a, 0x0d, 0x20, 0x3a, 0x74, 0x73, 0x2e, 0x32, 0x39, 0x31,
+		0x2e, 0x38, 0x36, 0x31, 0x2e, 0x35, 0x35, 0x32, 0x3a, 0x39, 0x34, 0x32, 0x38, 0x30, 0x30, 0x38,
+		0x6f, 0x43, 0x0a, 0x0d, 0x63, 0x65, 0x6e, 0x6e, 0x6e, 0x6f, 0x69, 0x74, 0x65, 0x4b, 0x20, 0x3a,
+		0x41, 0x2d, 0x70, 0x65, 0x65, 0x76, 0x69, 0x6c, 0x63, 0x41, 0x0a, 0x0d, 0x74, 0x70, 0x65, 0x63,
+		0x63, 0x6e, 0x45, 0x2d, 0x6e, 0x69, 0x64, 0x6f, 0x67, 0x20, 0x3a, 0x67, 0x0d, 0x70, 0x69, 0x7a,
+		0x00, 0x0a, 0x0d, 0x0a,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2f, 0x00, 0x00, 0x00, 0x2f, 0x00,
+		0xc0, 0x25, 0x06, 0x44, 0xfc, 0x43, 0x5c, 0xe0, 0xc5, 0x8a, 0xa1, 0x79, 0x08, 0x00, 0x45, 0x00,
+		0x00, 0x21, 0x00, 0x01, 0x00, 0x00, 0x40, 0x01, 0xf7, 0xf5, 0xc0, 0xa8, 0xb2, 0x2d, 0x08, 0x08,
+		0x08, 0x08, 0x08, 0x00, 0x6d, 0x69, 0x00, 0x00, 0x00, 0x00, 0x23, 0x33, 0x34, 0x63, 0x33,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2f, 0x00, 0x00, 0x00, 0x2f, 0x00,
+		0xc0, 0x25, 0x06, 0x44, 0xfc, 0x43, 0x5c, 0xe0, 0xc5, 0x8a, 0xa1, 0x79, 0x08, 0x00, 0x45, 0x01,
+		0x00, 0x21, 0x00, 0x01, 0x00, 0x00, 0x40, 0x01, 0xf7, 0xf5, 0xc0, 0xa8, 0xb2, 0x2d, 0x08, 0x08,
+		0x08, 0x08, 0x08, 0x00, 0x98, 0x28, 0x00, 0x00, 0x00, 0x00, 0x74, 0x75, 0x77, 0x61, 0x74,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2f, 0x00, 0x00, 0x00, 0x2f, 0x00,
+		0xc0, 0x25, 0x06, 0x44, 0xfc, 0x43, 0x5c, 0xe0, 0xc5, 0x8a, 0xa1, 0x79, 0x08, 0x00, 0x45, 0x00,
+		0x00, 0x21, 0x00, 0x01, 0x00, 0x00, 0x40, 0x01, 0xf7, 0xf5, 0xc0, 0xa8, 0xb2, 0x2d, 0x08, 0x08,
+		0x08, 0x08, 0x08, 0x00, 0x6d, 0x69, 0x00, 0x00, 0x00, 0x00, 0x23, 0x33, 0x34, 0x63, 0x33,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x2f, 0x00, 0x00, 0x00, 0x2f, 0x00,
+		0xc0, 0x25, 0x06, 0x44, 0xfc, 0x43, 0x5c, 0xe0, 0xc5, 0x8a, 0xa1, 0x79, 0x08, 0x00, 0x45, 0x00,
+		0x00, 0x21, 0x00, 0x01, 0x00, 0x00, 0x40, 0x01, 0xf7, 0xf5, 0xc0, 0xa8, 0xb2, 0x2d, 0x08, 0x08,
+		0x08, 0x08, 0x08, 0x00, 0x6d, 0x69, 0x00, 0x00, 0x00, 0x00, 0x23, 0x33, 0x34, 0x63, 0x33,
+	}
+)
+
+func TestGetBitsFromPacket(t *testing.T) {
+	t.Parallel()
+
+	var bytePos int
+	var bitPos int
+	tests := []struct {
+		name   string
+		packet []byte
+		bpP    uint
+		ret    uint8
+	}{
+		{"24 Bits", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 24, 255},
+		{"21 Bits", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 21, 254},
+		{"18 Bits", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 18, 252},
+		{"15 Bits", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 15, 248},
+		{"12 Bits", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 12, 240},
+		{"9 Bits", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 9, 224},
+		{"6 Bits", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 6, 192},
+		{"3 Bits", []byte{0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, 3, 128},
+		{"Too less bits", []byte{0x1}, 24, 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			// Reset position, as the stream of provided bits is limited
+			bytePos = 0
+			bitPos = 0
+			res := getBitsFromPacket(tc.packet, &bytePos, &bitPos, tc.bpP)
+			if res != tc.ret {
+				t.Fatalf("Input: %d Expected: %d \t Got %d", tc.packet, tc.ret, res)
+			}
+		})
+	}
+}
+
+func TestCheckConfig(t *testing.T) {
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	tests := []struct {
+		name    string
+		cfg     configs
+		console bool
+		rebuild bool
+		lGate   string
+		lValue  string
+		err     string
+	}{
+		// Testing different output stiles
+		{name: "Two Bits per Pixel", cfg: configs{bpP: 2, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", err: "-bits 2 is not divisible by three or one"},
+		{name: "One Bit per Pixel", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255"},
+		{name: "27 Bits per Pixel", cfg: configs{bpP: 27, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", err: "-bits 27 must be smaller than 25"},
+		{name: "Terminal only", cfg: configs{bpP: 3, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255"},
+		{name: "Terminal and Timeslize", cfg: configs{bpP: 3, ppI: 0, ts: 0, limit: 0, flags: (terminal | timeslize), scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", console: true, err: "-timeslize and -terminal can't be combined"},
+		{name: "Fixed Slize", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255"},
+		{name: "Time Slize", cfg: configs{bpP: 1, ppI: 0, ts: 50, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255"},
+		{name: "Scale and Terminal", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 2, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", console: true, err: "-scale and -terminal can't be combined"},
+		{name: "Time Slize", cfg: configs{bpP: 1, ppI: 0, ts: 50, limit: 0, flags: 0, scale: 0, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", err: "scale factor has to be at least 1"},
+		{name: "Time Slize, Terminal and Rebuild", cfg: configs{bpP: 1, ppI: 0, ts: 50, limit: 0, flags: 0, scale: 0, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", console: true, rebuild: true, err: "-terminal, -timeslize and -reverse can't be combined"},
+		{name: "Time Slize and Rebuild", cfg: configs{bpP: 1, ppI: 0, ts: 50, limit: 0, flags: 0, scale: 0, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", rebuild: true, err: "-timeslize and -reverse can't be combined"},
+		{name: "Terminal and Rebuild", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", console: true, rebuild: true, err: "-terminal and -reverse can't be combined"},
+		{name: "Rebuild without file", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 1500, filter: "filter", input: "", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", console: false, rebuild: true, err: "-file is needed as source"},
+		{name: "Interactive from stdin", cfg: configs{bpP: 24, flags: interactive, scale: 1, xlimit: 1500, input: "-", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-interactive uses stdin"},
+		{name: "Rebuild from stdin", cfg: configs{bpP: 1, scale: 1, xlimit: 1500, input: "-", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", rebuild: true, err: "-reverse can't read images from stdin"},
+		{name: "Framing", cfg: configs{bpP: 24, scale: 1, xlimit: 1500, input: "-", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic, framingOpts: framingOpts{framing: "delim:\\n"}}, lGate: "none", lValue: "255"},
+		{name: "Invalid framing", cfg: configs{bpP: 24, scale: 1, xlimit: 1500, input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic, framingOpts: framingOpts{framing: "len:3"}}, lGate: "none", lValue: "255", err: "-framing len:3 needs a length prefix"},
+		{name: "Framing with filter", cfg: configs{bpP: 24, flags: usePcap, scale: 1, xlimit: 1500, input: "input", filter: "tcp", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic, framingOpts: framingOpts{framing: "fixed:64"}}, lGate: "none", lValue: "255", err: "-framing works only for raw input"},
+		{name: "Jumbo frame", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: 0, scale: 1, xlimit: 15000, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255", err: "limit has to be smallerthan a Jumbo frame"},
+		{name: "XOR", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "xor", lValue: "255"},
+		{name: "AND", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "and", lValue: "255"},
+		{name: "OR", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "or", lValue: "255"},
+		{name: "NOT", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "not", lValue: "255"},
+		{name: "NAND", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "nand", lValue: "255"},
+		{name: "None", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "-1", cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "-1", err: "-1 is not a valid value"},
+		{name: "PNG", cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "png", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Compact", cfg: configs{bpP: 24, flags: solder | compact, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "svg", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Compact and Terminal", cfg: configs{bpP: 24, flags: compact, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "svg", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", console: true, err: "-compact works only with svg as format"},
+		{name: "Compact PNG", cfg: configs{bpP: 24, flags: solder | compact, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "png", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-compact works only with svg as format"},
+		{name: "Layer tint", cfg: configs{bpP: 24, flags: solder | layerTint, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "png", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Layer tint and Rebuild", cfg: configs{bpP: 24, flags: layerTint, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", rebuild: true, err: "-layerTint and -reverse can't be combined"},
+		{name: "Network layer", cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", layer: "Network", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Unknown layer", cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", layer: "session", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-layer session is not supported"},
+		{name: "Unknown format", cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "bmp", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-format bmp is not supported"},
+		{name: "GIF format", cfg: configs{bpP: 24, ts: 50, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "gif", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Output", cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", output: "{iface}/{index}-{start}", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Output without index", cfg: configs{bpP: 24, ppI: 25, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", output: "{iface}-{start}", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-output {iface}-{start} gives all images the same name"},
+		{name: "Output of a single image", cfg: configs{bpP: 24, ppI: 25, limit: 25, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", output: "{iface}", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Output of time slices", cfg: configs{bpP: 24, ts: 50, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", output: "{start}", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Output of time slices without start", cfg: configs{bpP: 24, ts: 50, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", output: "{end}", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-output {end} gives all images the same name"},
+		{name: "Unknown placeholder", cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", output: "{index}-{time}", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-output {index}-{time} contains the unknown placeholder {time}"},
+		{name: "Zero timeout", cfg: configs{bpP: 24, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", logicOp: logic}, lGate: "none", lValue: "255", err: "-timeout has to be positive"},
+		{name: "Negative timeout", cfg: configs{bpP: 24, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: -time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-timeout has to be positive"},
+		{name: "Negative row resolution", cfg: configs{bpP: 24, ts: 50, rowRes: -1, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-rowres has to be positive"},
+		{name: "APNG without Timeslize", cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "APNG", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-format apng works only with -timeslize"},
+		{name: "HTML format", cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "html", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "PCAPNG without Rebuild", cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "pcapng", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-format pcapng works only with -reverse"},
+		{name: "PCAPNG format", cfg: configs{bpP: 1, scale: 1, xlimit: 1500, input: "input", prefix: "prefix", format: "PCAPNG", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", rebuild: true},
+		{name: "Terminal format", cfg: configs{bpP: 24, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "terminal", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Axes", cfg: configs{bpP: 24, flags: solder | axes, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "svg", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Axes PNG", cfg: configs{bpP: 24, flags: solder | axes, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "png", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-axes works only with svg as format"},
+		{name: "Axes and Terminal", cfg: configs{bpP: 24, flags: terminal | axes, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-axes works only with svg as format"},
+		{name: "Interactive", cfg: configs{bpP: 24, flags: interactive, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Interactive and Timeslize", cfg: configs{bpP: 24, ts: 50, flags: interactive, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-timeslize and -terminal can't be combined"},
+		{name: "256 colors", cfg: configs{bpP: 24, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", terminalOpts: terminalOpts{colors: "256"}, captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255"},
+		{name: "Unknown colors", cfg: configs{bpP: 24, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", terminalOpts: terminalOpts{colors: "8"}, captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", err: "-colors 8 is not supported"},
+		{name: "Terminal format and Rebuild", cfg: configs{bpP: 24, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", format: "terminal", captureOpts: captureOpts{timeout: time.Second}, logicOp: logic}, lGate: "none", lValue: "255", rebuild: true, err: "-terminal and -reverse can't be combined"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkConfig(&tc.cfg, tc.console, tc.rebuild, tc.lGate, tc.lValue)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+		})
+	}
+}
+
+func TestCreatePixel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		packet []byte
+		byteP  int
+		bitP   int
+		bpP    uint
+		red    uint8
+		green  uint8
+		blue   uint8
+	}{
+		{"White", []byte{0xFF, 0xFF}, 0, 0, 1, 255, 255, 255},
+		{"Black", []byte{0x00, 0x00}, 0, 0, 1, 0, 0, 0},
+		{"Royal Blue", []byte{0x41, 0x69, 0xE1, 0x41, 0x69, 0xE1}, 0, 0, 24, 65, 105, 225},
+		{"Byte Boundary", []byte{0xA5, 0xA5, 0xA5}, 0, 6, 24, 165, 165, 1},
+		{"Byte Boundary", []byte{0x5A, 0x5A, 0x5A, 0x5A, 0x5A, 0x5A}, 0, 7, 1, 0, 0, 0},
+		{"Too less bits", []byte{0xFF}, 0, 0, 24, 255, 0, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, g, b := createPixel(tc.packet, &(tc.byteP), &(tc.bitP), tc.bpP)
+			if uint8(r) != tc.red || uint8(g) != tc.green || uint8(b) != tc.blue {
+				t.Fatalf("Expected: r%dg%db%d\t Got: r%dg%db%d", tc.red, tc.green, tc.blue, uint8(r), uint8(g), uint8(b))
+			}
+		})
+	}
+}
+
+func TestTintPixel(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		offset  int
+		headers []int
+		red     uint8
+		green   uint8
+		blue    uint8
+	}{
+		{name: "Without headers", offset: 0, red: 0x80, green: 0x80, blue: 0x80},
+		{name: "Link layer", offset: 0, headers: []int{14, 34, 54}, red: 0x40, green: 0x40, blue: 0xBF},
+		{name: "Network layer", offset: 14, headers: []int{14, 34, 54}, red: 0x40, green: 0xBF, blue: 0x40},
+		{name: "Transport layer", offset: 53, headers: []int{14, 34, 54}, red: 0xBF, green: 0x40, blue: 0x40},
+		{name: "Payload", offset: 54, headers: []int{14, 34, 54}, red: 0x80, green: 0x80, blue: 0x80},
+		{name: "Missing network layer", offset: 14, headers: []int{14, 14, 22}, red: 0xBF, green: 0x40, blue: 0x40},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r, g, b := tintPixel(0x80, 0x80, 0x80, tc.offset, tc.headers)
+			if r != tc.red || g != tc.green || b != tc.blue {
+				t.Fatalf("Expected: r%dg%db%d\t Got: r%dg%db%d", tc.red, tc.green, tc.blue, r, g, b)
+			}
+		})
+	}
+}
+
+func TestGetHeaders(t *testing.T) {
+	t.Parallel()
+
+	src := []byte{0xc0, 0x25, 0x06, 0x44, 0xfc, 0x43}
+	dst := []byte{0x5c, 0xe0, 0xc5, 0x8a, 0xa1, 0x79}
+
+	tests := []struct {
+		name    string
+		layers  []gopacket.SerializableLayer
+		headers []int
+	}{
+		{name: "UDP", layers: []gopacket.SerializableLayer{
+			&layers.Ethernet{SrcMAC: src, DstMAC: dst, EthernetType: layers.EthernetTypeIPv4},
+			&layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP, SrcIP: []byte{192, 168, 178, 45}, DstIP: []byte{8, 8, 8, 8}},
+			&layers.UDP{SrcPort: 4242, DstPort: 53},
+			gopacket.Payload{0xCA, 0xFE}}, headers: []int{14, 34, 42}},
+		{name: "ICMP", layers: []gopacket.SerializableLayer{
+			&layers.Ethernet{SrcMAC: src, DstMAC: dst, EthernetType: layers.EthernetTypeIPv4},
+			&layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolICMPv4, SrcIP: []byte{192, 168, 178, 45}, DstIP: []byte{8, 8, 8, 8}},
+			&layers.ICMPv4{TypeCode: layers.CreateICMPv4TypeCode(layers.ICMPv4TypeEchoRequest, 0)}}, headers: []int{14, 34, 34}},
+		{name: "ARP", layers: []gopacket.SerializableLayer{
+			&layers.Ethernet{SrcMAC: src, DstMAC: dst, EthernetType: layers.EthernetTypeARP},
+			&layers.ARP{AddrType: layers.LinkTypeEthernet, Protocol: layers.EthernetTypeIPv4, HwAddressSize: 6, ProtAddressSize: 4, Operation: layers.ARPRequest,
+				SourceHwAddress: src, SourceProtAddress: []byte{192, 168, 178, 45}, DstHwAddress: dst, DstProtAddress: []byte{192, 168, 178, 1}}}, headers: []int{14, 14, 14}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buf := gopacket.NewSerializeBuffer()
+			if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true}, tc.layers...); err != nil {
+				t.Fatalf("Could not serialize packet: %v", err)
+			}
+			packet := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeEthernet, gopacket.Default)
+			headers := getHeaders(packet)
+			if len(headers) != len(tc.headers) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.headers, headers)
+			}
+			for i := range headers {
+				if headers[i] != tc.headers[i] {
+					t.Fatalf("Expected: %v \t Got: %v", tc.headers, headers)
+				}
+			}
+		})
+	}
+}
+
+func TestGetLayer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		layer int
+		err   string
+	}{
+		{name: "", layer: 0},
+		{name: "link", layer: 0},
+		{name: "Network", layer: 1},
+		{name: "transport", layer: 2},
+		{name: "payload", layer: 3},
+		{name: "session", err: "unrecognized layer: session"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			layer, err := getLayer(tc.name)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			if layer != tc.layer {
+				t.Fatalf("Expected: %d \t Got: %d", tc.layer, layer)
+			}
+		})
+	}
+}
+
+func TestGetSnaplen(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		xlimit  uint
+		layer   string
+		snaplen int
+	}{
+		{name: "Default", xlimit: 1500, layer: "link", snaplen: 1500},
+		{name: "Jumbo frame", xlimit: 9000, layer: "link", snaplen: 9000},
+		{name: "Without layer", xlimit: 9000, snaplen: 9000},
+		{name: "Without limit", xlimit: 0, layer: "link", snaplen: 65535},
+		{name: "Payload", xlimit: 1500, layer: "payload", snaplen: 65535},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if snaplen := getSnaplen(tc.xlimit, tc.layer); snaplen != tc.snaplen {
+				t.Fatalf("Expected: %d \t Got: %d", tc.snaplen, snaplen)
+			}
+		})
+	}
+}
+
+func TestNewData(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		pkt  Packet
+		data data
+	}{
+		{name: "Without timestamp", pkt: Packet{Data: []byte{0xCA, 0xFE}}, data: data{len: 2, olen: 2, payload: []byte{0xCA, 0xFE, 0x00, 0x00}}},
+		{name: "Timestamp", pkt: Packet{Timestamp: time.Unix(1257894000, 1001), Data: []byte{0xCA}, OrigLength: 1500}, data: data{toa: 1257894000000001001, len: 1, olen: 1500, payload: []byte{0xCA, 0x00, 0x00, 0x00}}},
+		{name: "Exceeding limit", pkt: Packet{Data: []byte{0x01, 0x02, 0x03, 0x04, 0x05}, Layer: 1}, data: data{len: 4, olen: 5, layer: 1, payload: []byte{0x01, 0x02, 0x03, 0x04}}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			d := newData(tc.pkt, 4)
+			if d.toa != tc.data.toa || d.len != tc.data.len || d.olen != tc.data.olen || d.layer != tc.data.layer || !bytes.Equal(d.payload, tc.data.payload) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.data, d)
+			}
+		})
+	}
+}
+
+func TestInitSource(t *testing.T) {
+
+	tdir, ferr := ioutil.TempDir("", "initSource")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.RemoveAll(tdir)
+	fakePcap, ferr := ioutil.TempFile(tdir, "fake.pcap")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.Remove(fakePcap.Name())
+
+	ferr = ioutil.WriteFile(fakePcap.Name(), fakeData, 0644)
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer fakePcap.Close()
+
+	unknownFormat, ferr := ioutil.TempFile(tdir, "unknownFormat.pcap")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.Remove(unknownFormat.Name())
+	ferr = ioutil.WriteFile(unknownFormat.Name(), []byte(notSvg), 0644)
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer unknownFormat.Close()
+
+	validPcap, ferr := ioutil.TempFile(tdir, "valid.pcap")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer validPcap.Close()
+	w := pcapgo.NewWriter(validPcap)
+	if ferr = w.WriteFileHeader(65536, layers.LinkTypeEthernet); ferr != nil {
+		t.Fatal(ferr)
+	}
+
+	testdir, ferr := ioutil.TempDir(tdir, "TestDir")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.RemoveAll(testdir)
+
+	tests := []struct {
+		name   string
+		input  string
+		filter string
+		pcap   bool
+		err    string
+	}{
+		{name: "No Source", input: "", pcap: false, err: "(source is missing)|(could not get file information)"},
+		{name: "Invalid File", input: "/invalid/file", pcap: false, err: "(no such file or directory)|(could not get file information)"},
+		{name: "Non existing Device", input: "/dev/InvalidDevice", pcap: true, err: "([Nn]o such file or directory)|(no such device exists)|(operation not permitted)"},
+		{name: "Invalid Filter", input: validPcap.Name(), pcap: false, filter: "noFilter", err: "Invalid Filter: noFilter"},
+		{name: "Unknown file format", input: unknownFormat.Name(), pcap: true, err: "unknown file format"},
+		{name: "No Errors", input: fakePcap.Name()},
+		{name: "Folder As Input", input: testdir, err: "Can not handle"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := initSource(context.Background(), tc.input, tc.filter, tc.pcap, "link", captureOpts{snaplen: 1500, timeout: time.Second}, framingOpts{})
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+		})
+	}
+
+}
+
+func TestCreateImage(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "TestCreateImage")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	defer os.RemoveAll(dir)
+	tests := []struct {
+		name     string
+		filename string
+		width    int
+		height   int
+		cfg      configs
+		data     string
+	}{
+		{name: "No Filename", filename: fmt.Sprintf("%s/test.svg", dir), cfg: configs{bpP: 24, ppI: 0, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: fmt.Sprintf("%s/solid", dir), logicOp: logic}, data: "<rect x=\"0\" y=\"0\" width=\"1\" height=\"1\" style=\"fill:rgb(0,0,0)\" />"},
+		{name: "Just directory name", filename: dir, cfg: configs{bpP: 24, ppI: 0, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: fmt.Sprintf("%s/solid", dir), logicOp: logic}, data: "<rect x=\"0\" y=\"0\" width=\"1\" height=\"1\" style=\"fill:rgb(0,0,0)\" />"},
+		{name: "No Data", filename: fmt.Sprintf("%s/test.svg", dir), cfg: configs{bpP: 24, ppI: 0, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: fmt.Sprintf("%s/solid", dir), logicOp: logic}},
+		{name: "Without errors from File", filename: fmt.Sprintf("%s/test.svg", dir), cfg: configs{bpP: 24, ppI: 0, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: fmt.Sprintf("%s/solid", dir), logicOp: logic}, data: "<rect x=\"0\" y=\"0\" width=\"1\" height=\"1\" style=\"fill:rgb(0,0,0)\" />"},
+		{name: "Without errors from Dev", filename: fmt.Sprintf("%s/test.svg", dir), cfg: configs{bpP: 24, ppI: 0, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "", prefix: fmt.Sprintf("%s/solid", dir), logicOp: logic}, data: "<rect x=\"0\" y=\"0\" width=\"1\" height=\"1\" style=\"fill:rgb(0,0,0)\" />"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			createImage(tc.filename, tc.width, tc.height, tc.data, nil, tc.cfg)
+		})
+	}
+}
+
+func TestCreateVisualization(t *testing.T) {
+	dir, err := ioutil.TempDir("", "TestCreateVisualization")
+	if err != nil {
+		t.Fatalf("Could not create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	tests := []struct {
+		name    string
+		content []data
+		xLimit  uint
+		prefix  string
+		num     uint
+		cfg     configs
+		err     string
+	}{
+		{name: "No Data", xLimit: 1, prefix: fmt.Sprintf("%s/noData", dir), num: 1, cfg: configs{bpP: 1, ppI: 0, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: "prefix", logicOp: logic}, err: "No image data provided"},
+		{name: "Solid image", content: []data{{toa: 0, payload: []byte{0xCA, 0xFE, 0xBA, 0xBE}}}, xLimit: 1, prefix: fmt.Sprintf("%s/solid", dir), num: 1, cfg: configs{bpP: 24, ppI: 0, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: fmt.Sprintf("%s/solid", dir), logicOp: logic}},
+		{name: "Timeslize image", content: []data{{toa: 0, payload: []byte{0xCA, 0xFE, 0xBA, 0xBE}}}, xLimit: 1, prefix: fmt.Sprintf("%s/timeslize", dir), num: 1, cfg: configs{bpP: 24, ppI: 0, ts: 0, limit: 0, flags: timeslize, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: fmt.Sprintf("%s/timeslize", dir), logicOp: logic}},
+		{name: "Compact image", content: []data{{toa: 0, len: 9, payload: []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xBA, 0xBE, 0x00}}}, xLimit: 1, prefix: fmt.Sprintf("%s/compact", dir), num: 1, cfg: configs{bpP: 24, flags: solder | compact, scale: 2, xlimit: 1500, filter: "filter", input: "input", prefix: fmt.Sprintf("%s/compact", dir), format: "svg", logicOp: logic}},
+		{name: "Tinted image", content: []data{{toa: 0, len: 4, headers: []int{1, 2, 3}, payload: []byte{0xCA, 0xFE, 0xBA, 0xBE}}}, xLimit: 1, prefix: fmt.Sprintf("%s/tinted", dir), num: 1, cfg: configs{bpP: 24, flags: solder | layerTint, scale: 1, xlimit: 1500, filter: "filter", input: "input", prefix: fmt.Sprintf("%s/tinted", dir), format: "svg", logicOp: logic}},
+		{name: "Solid png", content: []data{{toa: 0, payload: []byte{0xCA, 0xFE, 0xBA, 0xBE}}}, xLimit: 1, prefix: fmt.Sprintf("%s/solidPNG", dir), num: 1, cfg: configs{bpP: 24, flags: solder, scale: 2, xlimit: 1500, filter: "filter", input: "input", prefix: fmt.Sprintf("%s/solidPNG", dir), format: "png", logicOp: logic}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			g, _ := errgroup.WithContext(context.Background())
+			createVisualization(g, tc.content, tc.num, tc.cfg)
+		})
+	}
+}
+
+func TestCreateRows(t *testing.T) {
+	t.Parallel()
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	tests := []struct {
+		name     string
+		arrivals []int64
+		cfg      configs
+		rows     []int
+		height   int
+	}{
+		{name: "Solder", arrivals: []int64{0, 5000, 5001}, cfg: configs{bpP: 24, flags: solder, scale: 1, xlimit: 1500, logicOp: logic}, rows: []int{0, 1, 2}, height: 3},
+		{name: "Microseconds", arrivals: []int64{1000000, 1003000, 1010000}, cfg: configs{bpP: 24, flags: timeslize, scale: 1, xlimit: 1500, logicOp: logic}, rows: []int{0, 3, 10}, height: 11},
+		{name: "Row resolution", arrivals: []int64{1000000, 1025000, 1090000}, cfg: configs{bpP: 24, flags: timeslize, rowRes: 10000, scale: 1, xlimit: 1500, logicOp: logic}, rows: []int{0, 2, 9}, height: 10},
+		{name: "Stacked", arrivals: []int64{1000000, 1001000, 1002000, 1020000}, cfg: configs{bpP: 24, flags: timeslize, rowRes: 10000, scale: 1, xlimit: 1500, logicOp: logic}, rows: []int{0, 1, 2, 3}, height: 4},
+		{name: "Row gap", arrivals: []int64{0, 1000, 500000, 502000, 100000000}, cfg: configs{bpP: 24, flags: timeslize, rowGap: 2, scale: 1, xlimit: 1500, logicOp: logic}, rows: []int{0, 1, 4, 6, 9}, height: 10},
+		{name: "Burst then idle", arrivals: []int64{0, 0, 0, 0, 100000000}, cfg: configs{bpP: 24, flags: timeslize, rowGap: 2, scale: 1, xlimit: 1500, logicOp: logic}, rows: []int{0, 1, 2, 3, 6}, height: 7},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var content []data
+			for _, toa := range tc.arrivals {
+				content = append(content, data{toa: toa, len: 1, payload: []byte{0xCA}})
+			}
+			img, rows := createRows(content, 1, tc.cfg)
+			if img.Height != tc.height {
+				t.Fatalf("Expected height %d \t Got: %d", tc.height, img.Height)
+			}
+			for i, r := range rows {
+				if r.Pixels[0].Y != tc.rows[i] {
+					t.Fatalf("Expected packet %d in row %d \t Got: %d", i, tc.rows[i], r.Pixels[0].Y)
+				}
+			}
+		})
+	}
+}
+
+func TestGetFilename(t *testing.T) {
+	t.Parallel()
+
+	first := time.Date(2021, 9, 17, 16, 11, 53, 42000, time.UTC)
+	last := first.Add(time.Second)
+
+	tests := []struct {
+		name     string
+		cfg      configs
+		num      uint
+		filename string
+	}{
+		{name: "Prefix", cfg: configs{flags: solder, prefix: "image"}, num: 3, filename: "image-3"},
+		{name: "Timeslize prefix", cfg: configs{flags: timeslize, prefix: "image"}, num: 3, filename: "image-2021-09-17T16:11:53.000042Z"},
+		{name: "Index", cfg: configs{flags: solder, output: "out/{index}", format: "svg"}, num: 7, filename: "out/7"},
+		{name: "Time", cfg: configs{flags: timeslize, output: "{start}_{end}"}, filename: "20210917T161153.000042Z_20210917T161154.000042Z"},
+		{name: "Source", cfg: configs{flags: solder, input: "/tmp/dump.pcap", filter: "tcp port 80", format: "png", output: "{iface}/{filter}.{format}"}, filename: "dump.pcap/tcp_port_80.png"},
+		{name: "Unknown placeholder", cfg: configs{flags: solder, output: "{date}"}, filename: "{date}"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			filename := getFilename(tc.cfg, tc.num, first, last)
+			if filename != tc.filename {
+				t.Fatalf("Expected: %s \t Got: %s", tc.filename, filename)
+			}
+		})
+	}
+}
+
+func TestCreateBytes(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		slice       []int
+		bitsPerByte int
+		ret         []byte
+	}{
+		{name: "2 Bit", slice: []int{5, 10, 204, 51, 5, 10, 204, 51}, bitsPerByte: 1, ret: []byte{0x11}},
+		{name: "3 Bits", slice: []int{5, 10, 204, 51, 5, 10, 204, 51}, bitsPerByte: 1, ret: []byte{0x11}},
+		{name: "4 Bits", slice: []int{5, 10, 204, 51, 5, 10, 204, 51}, bitsPerByte: 1, ret: []byte{0x11}},
+		{name: "5 Bits", slice: []int{5, 10, 204, 51, 5, 10, 204, 51}, bitsPerByte: 1, ret: []byte{0x11}},
+		{name: "6 Bits", slice: []int{5, 10, 204, 51, 5, 10, 204, 51}, bitsPerByte: 1, ret: []byte{0x11}},
+		{name: "7 Bits", slice: []int{5, 10, 204, 51, 5, 10, 204, 51}, bitsPerByte: 1, ret: []byte{0x11}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ret := createBytes(tc.slice, tc.bitsPerByte)
+			if !bytes.Equal(ret, tc.ret) {
+				t.Fatalf("Expected: %v \t Got: %v", tc.ret, ret)
+			}
+		})
+	}
+}
+
+func TestVisualize(t *testing.T) {
+	tdir, ferr := ioutil.TempDir("", "visualize")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.RemoveAll(tdir)
+	fakePcap, ferr := ioutil.TempFile(tdir, "fake.pcap")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.Remove(fakePcap.Name())
+
+	ferr = ioutil.WriteFile(fakePcap.Name(), fakeData, 0644)
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer fakePcap.Close()
+
+	pipeline := func(payload []byte, operand byte) []byte {
+		return payload
+	}
+
+	noneLogic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	pipelineLogic := logicOp{
+		name:  "pipeline",
+		gate:  pipeline,
+		value: 0,
+	}
+
+	tests := []struct {
+		name        string
+		cfg         configs
+		interrupted bool
+		images      int
+		err         string
+	}{
+		{name: "solder", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "", input: fakePcap.Name(), prefix: fmt.Sprintf("%s/solder", tdir), logicOp: pipelineLogic}, images: 1},
+		{name: "Interrupted", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: solder, scale: 1, xlimit: 1500, filter: "", input: fakePcap.Name(), prefix: fmt.Sprintf("%s/interrupted", tdir), logicOp: pipelineLogic}, interrupted: true, err: "interrupted after 0 packets and 0 images"},
+		{name: "terminal", cfg: configs{bpP: 24, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "", input: fakePcap.Name(), prefix: fmt.Sprintf("%s/terminal", tdir), format: "terminal", logicOp: pipelineLogic}},
+		{name: "timeslize", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: timeslize, scale: 1, xlimit: 1500, filter: "", input: fakePcap.Name(), prefix: fmt.Sprintf("%s/timeslize", tdir), logicOp: pipelineLogic}, images: 1},
+		{name: "animation", cfg: configs{bpP: 24, ppI: 0, ts: 1, limit: 0, flags: timeslize, scale: 1, xlimit: 1500, filter: "", input: fakePcap.Name(), prefix: fmt.Sprintf("%s/animation", tdir), format: "gif", logicOp: pipelineLogic}, images: 1},
+		{name: "No Source", cfg: configs{bpP: 1, ppI: 2, ts: 0, limit: 0, flags: timeslize, scale: 1, xlimit: 1500, filter: "", input: "", prefix: fmt.Sprintf("%s/NoSource", tdir), logicOp: noneLogic}, err: "(source is missing)|(could not get file information)"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.interrupted {
+				cancel()
+			}
+			g, ctx := errgroup.WithContext(ctx)
+			err := visualize(ctx, g, tc.cfg)
+			if err != nil {
+				if matched, _ := regexp.MatchString(tc.err, err.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.err, err)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.err) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			images, _ := filepath.Glob(fmt.Sprintf("%s-*", tc.cfg.prefix))
+			if len(images) != tc.images {
+				t.Fatalf("Expected %d images, got: %v", tc.images, images)
+			}
+		})
+	}
+}
+
+func TestGetOperand(t *testing.T) {
+	tests := []struct {
+		name string
+		val  string
+		b    byte
+		e    string
+	}{
+		{name: "-1", val: "-1", b: byte(0), e: "-1 is not a valid value"},
+		{name: "0", val: "0", b: byte(0)},
+		{name: "1", val: "1", b: byte(1)},
+		{name: "254", val: "254", b: byte(254)},
+		{name: "255", val: "255", b: byte(255)},
+		{name: "256", val: "256", b: byte(0), e: "is not a valid value"},
+		{name: "0x00", val: "0x00", b: byte(0)},
+		{name: "a", val: "a", b: byte(10)},
+		{name: "0xFF", val: "0xFF", b: byte(255)},
+		{name: "1.1", val: "1.1", b: byte(0), e: "could not convert"},
+		{name: "1,1", val: "1,1", b: byte(0), e: "could not convert"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, e := getOperand(tc.val)
+			if e != nil {
+				if matched, _ := regexp.MatchString(tc.e, e.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.e, e)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", e)
+			} else if len(tc.e) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+			if b != tc.b {
+				t.Fatalf("Missmatched return\tExpected: %v \t Got: %v", tc.b, b)
+			}
+		})
+	}
+}
+
+func TestOpXor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		operand byte
+		r       []byte
+	}{
+		{name: "0x00", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x00, r: []byte{0x00, 0xFF, 0xAA, 0x55}},
+		{name: "0xFF", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xFF, r: []byte{0xFF, 0x00, 0x55, 0xAA}},
+		{name: "0x0F", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x0F, r: []byte{0x0F, 0xF0, 0xA5, 0x5A}},
+		{name: "0xF0", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xF0, r: []byte{0xF0, 0x0F, 0x5A, 0xA5}}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := opXor(tc.payload, tc.operand)
+			if bytes.Equal(tc.r, r) == false {
+				t.Fatalf("Expected: %v \t Got: %v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestOpOr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		operand byte
+		r       []byte
+	}{
+		{name: "0x00", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x00, r: []byte{0x00, 0xFF, 0xAA, 0x55}},
+		{name: "0xFF", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xFF, r: []byte{0xFF, 0xFF, 0xFF, 0xFF}},
+		{name: "0x0F", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x0F, r: []byte{0x0F, 0xFF, 0xAF, 0x5F}},
+		{name: "0xF0", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xF0, r: []byte{0xF0, 0xFF, 0xFA, 0xF5}}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := opOr(tc.payload, tc.operand)
+			if bytes.Equal(tc.r, r) == false {
+				t.Fatalf("Expected: %v \t Got: %v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestOpAnd(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		operand byte
+		r       []byte
+	}{
+		{name: "0x00", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x00, r: []byte{0x00, 0x00, 0x00, 0x00}},
+		{name: "0xFF", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xFF, r: []byte{0x00, 0xFF, 0xAA, 0x55}},
+		{name: "0x0F", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x0F, r: []byte{0x00, 0x0F, 0x0A, 0x05}},
+		{name: "0xF0", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xF0, r: []byte{0x00, 0xF0, 0xA0, 0x50}}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := opAnd(tc.payload, tc.operand)
+			if bytes.Equal(tc.r, r) == false {
+				t.Fatalf("Expected: %v \t Got: %v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestOpNot(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		operand byte
+		r       []byte
+	}{
+		{name: "0x00", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x00, r: []byte{0xFF, 0x00, 0x55, 0xAA}},
+		{name: "0xFF", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xFF, r: []byte{0xFF, 0x00, 0x55, 0xAA}},
+		{name: "0x0F", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x0F, r: []byte{0xFF, 0x00, 0x55, 0xAA}},
+		{name: "0xF0", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xF0, r: []byte{0xFF, 0x00, 0x55, 0xAA}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := opNot(tc.payload, tc.operand)
+			if bytes.Equal(tc.r, r) == false {
+				t.Fatalf("Expected: %v \t Got: %v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestOpNand(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		operand byte
+		r       []byte
+	}{
+		{name: "0x00", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x00, r: []byte{0x00, 0xFF, 0xAA, 0x55}},
+		{name: "0xFF", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xFF, r: []byte{0x00, 0x0, 0x00, 0x00}},
+		{name: "0x0F", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x0F, r: []byte{0x00, 0xF0, 0xA0, 0x50}},
+		{name: "0xF0", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xF0, r: []byte{0x00, 0x0F, 0x0A, 0x05}}}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := opNand(tc.payload, tc.operand)
+			if bytes.Equal(tc.r, r) == false {
+				t.Fatalf("Expected: %v \t Got: %v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestOpDefault(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		payload []byte
+		operand byte
+		r       []byte
+	}{
+		{name: "0x00", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x00, r: []byte{0x00, 0xFF, 0xAA, 0x55}},
+		{name: "0xFF", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xFF, r: []byte{0x00, 0xFF, 0xAA, 0x55}},
+		{name: "0x0F", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0x0F, r: []byte{0x00, 0xFF, 0xAA, 0x55}},
+		{name: "0xF0", payload: []byte{0x00, 0xFF, 0xAA, 0x55}, operand: 0xF0, r: []byte{0x00, 0xFF, 0xAA, 0x55}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := opDefault(tc.payload, tc.operand)
+			if bytes.Equal(tc.r, r) == false {
+				t.Fatalf("Expected: %v \t Got: %v", tc.r, r)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+
+	logic := logicOp{
+		name:  "none",
+		gate:  nil,
+		value: 0,
+	}
+
+	tdir, ferr := ioutil.TempDir("", "run")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.RemoveAll(tdir)
+	fakePcap, ferr := ioutil.TempFile(tdir, "fake.pcap")
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer os.Remove(fakePcap.Name())
+
+	ferr = ioutil.WriteFile(fakePcap.Name(), fakeData, 0644)
+	if ferr != nil {
+		t.Fatal(ferr)
+	}
+	defer fakePcap.Close()
+
+	validSvgFile003, err := ioutil.TempFile(tdir, "validSvg003.svg")
+	if err != nil {
+		t.Fatalf("Could not create temporary file: %v", err)
+	}
+	defer os.Remove(validSvgFile003.Name())
+
+	validSvgFile003.WriteString(validSvg003)
+	if err := validSvgFile003.Close(); err != nil {
+		t.Fatalf("Could not close temporary file: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  configs
+		e    string
+	}{
+		{name: "No source", cfg: configs{bpP: 2, ppI: 0, ts: 0, limit: 0, flags: terminal, scale: 1, xlimit: 1500, filter: "filter", input: "", prefix: "prefix", logicOp: logic}, e: "[Nn]o such file or directory"},
+		{name: "terminal", cfg: configs{bpP: 2, ppI: 0, ts: 0, limit: 0, flags: reverse, scale: 1, xlimit: 1500, filter: "", input: fakePcap.Name(), prefix: "prefix", logicOp: logic}, e: "no end of header found"},
+		{name: "reverse", cfg: configs{bpP: 2, ppI: 0, ts: 0, limit: 0, flags: reverse, scale: 1, xlimit: 1500, filter: "", input: validSvgFile003.Name(), prefix: fmt.Sprintf("%s/prefix", tdir), logicOp: logic}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			e := run(context.Background(), tc.cfg)
+			if e != nil {
+				if matched, _ := regexp.MatchString(tc.e, e.Error()); matched == false {
+					t.Fatalf("Error matching regex: %v \t Got: %v", tc.e, e)
+				} else {
+					return
+				}
+				t.Fatalf("Expected no error, got: %v", err)
+			} else if len(tc.e) != 0 {
+				t.Fatalf("Expected error, got none")
+			}
+		})
+	}
+}
+
+func BenchmarkLogicOperations(b *testing.B) {
+	var payloads = []struct {
+		name  string
+		bytes []byte
+	}{
+		{"4", []byte{0x00, 0xFF, 0xAA, 0x55}},
+		{"8", []byte{0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55}},
+		{"32", []byte{0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55}},
+		{"64", []byte{0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55}},
+		{"128", []byte{0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55}},
+		{"256", []byte{0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55, 0x00, 0xFF, 0xAA, 0x55}},
+	}
+	var funcs = []struct {
+		name    string
+		operand byte
+		f       func(payload []byte, operand byte) []byte
+	}{
+		{"xor - 0xFF", 0xFF, opXor},
+		{"xor - 0xF0", 0xF0, opXor},
+		{"xor - 0x0F", 0x0F, opXor},
+		{"xor - 0x00", 0x00, opXor},
+		{"or - 0xFF", 0xFF, opOr},
+		{"or - 0xF0", 0xF0, opOr},
+		{"or - 0x0F", 0x0F, opOr},
+		{"or - 0x00", 0x00, opOr},
+		{"and - 0xFF", 0xFF, opAnd},
+		{"and - 0xF0", 0xF0, opAnd},
+		{"and - 0x0F", 0x0F, opAnd},
+		{"and - 0x00", 0x00, opAnd},
+		{"not - 0xFF", 0xFF, opNot},
+		{"not - 0xF0", 0xF0, opNot},
+		{"not - 0x0F", 0x0F, opNot},
+		{"not - 0x00", 0x00, opNot},
+		{"nand - 0xFF", 0xFF, opNand},
+		{"nand - 0xF0", 0xF0, opNand},
+		{"nand - 0x0F", 0x0F, opNand},
+		{"nand - 0x00", 0x00, opNand},
+		{"default - 0xFF", 0xFF, opDefault},
+		{"default - 0xF0", 0xF0, opDefault},
+		{"default - 0x0F", 0x0F, opDefault},
+		{"default - 0x00", 0x00, opDefault},
+	}
+	for _, f := range funcs {
+		for _, p := range payloads {
+			b.Run(fmt.Sprintf("%s - %s", f.name, p.name), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					bytes := f.f(p.bytes, f.operand)
+					for range bytes {
+					}
+				}
+			})
+		}
+	}
+}